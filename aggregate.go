@@ -0,0 +1,303 @@
+package filterobject
+
+import (
+	"fmt"
+	"github.com/xafelium/filter"
+	"math/big"
+	"reflect"
+)
+
+// AggregateFunction is the function of an Aggregation.
+type AggregateFunction string
+
+const (
+	CountFunction    AggregateFunction = "count"
+	SumFunction      AggregateFunction = "sum"
+	MinFunction      AggregateFunction = "min"
+	MaxFunction      AggregateFunction = "max"
+	AvgFunction      AggregateFunction = "avg"
+	DistinctFunction AggregateFunction = "distinct"
+)
+
+// Aggregation aggregates the values of a field of the objects of a group into a result with
+// the name. Nil values are ignored.
+type Aggregation struct {
+	Name     string
+	Function AggregateFunction
+	Field    string
+}
+
+// Count counts the objects of a group.
+func Count(name string) Aggregation {
+	return Aggregation{Name: name, Function: CountFunction}
+}
+
+// CountField counts the objects of a group whose field is not nil.
+func CountField(name string, field string) Aggregation {
+	return Aggregation{Name: name, Function: CountFunction, Field: field}
+}
+
+// Sum sums up the numeric values of the field as an exact *big.Rat.
+func Sum(name string, field string) Aggregation {
+	return Aggregation{Name: name, Function: SumFunction, Field: field}
+}
+
+// Min returns the lowest value of the field or nil if there are no values.
+func Min(name string, field string) Aggregation {
+	return Aggregation{Name: name, Function: MinFunction, Field: field}
+}
+
+// Max returns the highest value of the field or nil if there are no values.
+func Max(name string, field string) Aggregation {
+	return Aggregation{Name: name, Function: MaxFunction, Field: field}
+}
+
+// Avg returns the average of the numeric values of the field as an exact *big.Rat or nil if
+// there are no values.
+func Avg(name string, field string) Aggregation {
+	return Aggregation{Name: name, Function: AvgFunction, Field: field}
+}
+
+// Distinct returns the distinct values of the field as []any in the order of their first occurrence.
+func Distinct(name string, field string) Aggregation {
+	return Aggregation{Name: name, Function: DistinctFunction, Field: field}
+}
+
+// Group contains the values of the group by fields of a group and the results of the aggregations.
+type Group struct {
+	Key     map[string]any
+	Results map[string]any
+}
+
+// Aggregate groups the objects to which the condition applies by the values of the fields
+// and aggregates each group. The groups are returned in the order of their first object.
+// Without fields, all objects form a single group.
+func Aggregate[T any](objects []T, condition filter.Condition, groupBy []string, aggregations ...Aggregation) ([]Group, error) {
+	compiled, err := Compile(condition)
+	if err != nil {
+		return nil, err
+	}
+	for _, aggregation := range aggregations {
+		if _, err := newAggregator(aggregation); err != nil {
+			return nil, err
+		}
+	}
+
+	type group struct {
+		key         map[string]any
+		aggregators []aggregator
+	}
+	var groups []*group
+	groupsByKey := make(map[string]*group)
+	for _, obj := range objects {
+		applies, err := compiled.Applies(obj)
+		if err != nil {
+			return nil, err
+		}
+		if !applies {
+			continue
+		}
+		key := make(map[string]any, len(groupBy))
+		groupKey := ""
+		for _, field := range groupBy {
+			v, err := getField(obj, field)
+			if err != nil {
+				return nil, err
+			}
+			value, valueKey, err := aggregateValue(v)
+			if err != nil {
+				return nil, fmt.Errorf("cannot group by field '%s': %w", field, err)
+			}
+			key[field] = value
+			groupKey += valueKey + "\x00"
+		}
+		g, found := groupsByKey[groupKey]
+		if !found {
+			g = &group{key: key}
+			for _, aggregation := range aggregations {
+				a, _ := newAggregator(aggregation)
+				g.aggregators = append(g.aggregators, a)
+			}
+			groups = append(groups, g)
+			groupsByKey[groupKey] = g
+		}
+		for i, aggregation := range aggregations {
+			var v reflect.Value
+			if aggregation.Field != "" {
+				v, err = getField(obj, aggregation.Field)
+				if err != nil {
+					return nil, err
+				}
+			}
+			if err := g.aggregators[i].add(v); err != nil {
+				return nil, fmt.Errorf("cannot aggregate field '%s': %w", aggregation.Field, err)
+			}
+		}
+	}
+
+	if len(groups) == 0 && len(groupBy) == 0 {
+		g := &group{key: map[string]any{}}
+		for _, aggregation := range aggregations {
+			a, _ := newAggregator(aggregation)
+			g.aggregators = append(g.aggregators, a)
+		}
+		groups = append(groups, g)
+	}
+	result := make([]Group, 0, len(groups))
+	for _, g := range groups {
+		results := make(map[string]any, len(aggregations))
+		for i, aggregation := range aggregations {
+			results[aggregation.Name] = g.aggregators[i].result()
+		}
+		result = append(result, Group{Key: g.key, Results: results})
+	}
+	return result, nil
+}
+
+// aggregateValue returns the value and a key which is equal for equal values.
+func aggregateValue(v reflect.Value) (any, string, error) {
+	value := indirect(v)
+	if !value.IsValid() {
+		return nil, "nil", nil
+	}
+	if !v.CanInterface() {
+		return nil, "", fmt.Errorf("value is not exported")
+	}
+	category := indexCategoryOf(value.Type())
+	if key, ok := indexKey(category, value); ok {
+		return v.Interface(), fmt.Sprintf("%d:%s", category, key), nil
+	}
+	return v.Interface(), valueKey(value.Interface()), nil
+}
+
+type aggregator interface {
+	add(v reflect.Value) error
+	result() any
+}
+
+func newAggregator(aggregation Aggregation) (aggregator, error) {
+	if aggregation.Field == "" && aggregation.Function != CountFunction {
+		return nil, fmt.Errorf("%s aggregation '%s' must have a field", aggregation.Function, aggregation.Name)
+	}
+	switch aggregation.Function {
+	case CountFunction:
+		return &countAggregator{all: aggregation.Field == ""}, nil
+	case SumFunction:
+		return &sumAggregator{sum: new(big.Rat)}, nil
+	case AvgFunction:
+		return &sumAggregator{sum: new(big.Rat), avg: true}, nil
+	case MinFunction:
+		return &extremeAggregator{sign: -1}, nil
+	case MaxFunction:
+		return &extremeAggregator{sign: 1}, nil
+	case DistinctFunction:
+		return &distinctAggregator{keys: make(map[string]bool), values: []any{}}, nil
+	}
+	return nil, fmt.Errorf("unknown aggregate function: %s", aggregation.Function)
+}
+
+type countAggregator struct {
+	all   bool
+	count int
+}
+
+func (a *countAggregator) add(v reflect.Value) error {
+	if a.all || indirect(v).IsValid() {
+		a.count++
+	}
+	return nil
+}
+
+func (a *countAggregator) result() any {
+	return a.count
+}
+
+type sumAggregator struct {
+	avg   bool
+	sum   *big.Rat
+	count int64
+}
+
+func (a *sumAggregator) add(v reflect.Value) error {
+	v = indirect(v)
+	if !v.IsValid() {
+		return nil
+	}
+	if !isNumber(v) {
+		return fmt.Errorf("cannot sum values of type %s", v.Type())
+	}
+	n, ok := toNumber(v)
+	if !ok || n.inf != 0 {
+		return fmt.Errorf("cannot sum value %v", v)
+	}
+	a.sum.Add(a.sum, n.rat)
+	a.count++
+	return nil
+}
+
+func (a *sumAggregator) result() any {
+	if !a.avg {
+		return a.sum
+	}
+	if a.count == 0 {
+		return nil
+	}
+	return new(big.Rat).Quo(a.sum, new(big.Rat).SetInt64(a.count))
+}
+
+type extremeAggregator struct {
+	sign  int
+	value reflect.Value
+}
+
+func (a *extremeAggregator) add(v reflect.Value) error {
+	if !indirect(v).IsValid() {
+		return nil
+	}
+	if !a.value.IsValid() {
+		if !v.CanInterface() {
+			return fmt.Errorf("value is not exported")
+		}
+		a.value = v
+		return nil
+	}
+	c, err := compareValues(v, a.value)
+	if err != nil {
+		return err
+	}
+	if c*a.sign > 0 {
+		a.value = v
+	}
+	return nil
+}
+
+func (a *extremeAggregator) result() any {
+	if !a.value.IsValid() {
+		return nil
+	}
+	return a.value.Interface()
+}
+
+type distinctAggregator struct {
+	keys   map[string]bool
+	values []any
+}
+
+func (a *distinctAggregator) add(v reflect.Value) error {
+	if !indirect(v).IsValid() {
+		return nil
+	}
+	value, key, err := aggregateValue(v)
+	if err != nil {
+		return err
+	}
+	if !a.keys[key] {
+		a.keys[key] = true
+		a.values = append(a.values, value)
+	}
+	return nil
+}
+
+func (a *distinctAggregator) result() any {
+	return a.values
+}
@@ -0,0 +1,124 @@
+package filterobject
+
+import (
+	"github.com/stretchr/testify/require"
+	"github.com/xafelium/filter"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// ratStrings replaces the *big.Rat results of the groups with their string representation.
+func ratStrings(groups []Group) []Group {
+	for _, group := range groups {
+		for name, result := range group.Results {
+			if r, ok := result.(*big.Rat); ok {
+				group.Results[name] = r.RatString()
+			}
+		}
+	}
+	return groups
+}
+
+func TestAggregate(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	objects := []TestObject{
+		{Id: 1, TaskType: "magic", Name: "a", CreatedAt: start},
+		{Id: 2, TaskType: "chore", Name: "b", CreatedAt: start.Add(time.Hour)},
+		{Id: 3, TaskType: "magic", Name: "a", CreatedAt: start.Add(2 * time.Hour)},
+		{Id: 4, TaskType: "magic", Name: "c", CreatedAt: start.Add(3 * time.Hour), ChildObject: &TestObject{Id: 10}},
+		{Id: 5, TaskType: "chore", Name: "d", CreatedAt: start.Add(4 * time.Hour)},
+	}
+	groups, err := Aggregate(objects, filter.GreaterThan("createdAt", start), []string{"taskType"},
+		Count("count"),
+		CountField("children", "childObject"),
+		Sum("sum", "id"),
+		Avg("avg", "id"),
+		Min("first", "createdAt"),
+		Max("last", "createdAt"),
+		Distinct("names", "name"),
+		Sum("childSum", "childObject.id"),
+	)
+	require.NoError(t, err)
+	require.Equal(t, []Group{
+		{
+			Key: map[string]any{"taskType": "chore"},
+			Results: map[string]any{
+				"count":    2,
+				"children": 0,
+				"sum":      "7",
+				"avg":      "7/2",
+				"first":    start.Add(time.Hour),
+				"last":     start.Add(4 * time.Hour),
+				"names":    []any{"b", "d"},
+				"childSum": "0",
+			},
+		},
+		{
+			Key: map[string]any{"taskType": "magic"},
+			Results: map[string]any{
+				"count":    2,
+				"children": 1,
+				"sum":      "7",
+				"avg":      "7/2",
+				"first":    start.Add(2 * time.Hour),
+				"last":     start.Add(3 * time.Hour),
+				"names":    []any{"a", "c"},
+				"childSum": "10",
+			},
+		},
+	}, ratStrings(groups))
+}
+
+func TestAggregateMultipleFields(t *testing.T) {
+	objects := []BigTestObject{
+		{Quantity: 1, Size: 1, Weight: 0.5, Amount: big.NewRat(1, 3)},
+		{Quantity: 1, Size: 2, Weight: 1.5, Amount: big.NewRat(1, 3)},
+		{Quantity: 1, Size: 1, Weight: 2},
+		{Quantity: 2, Size: 1, Weight: 3},
+	}
+	groups, err := Aggregate(objects, nil, []string{"quantity", "size"},
+		Sum("weight", "weight"),
+		Avg("amount", "amount"),
+		Min("minAmount", "amount"),
+	)
+	require.NoError(t, err)
+	ratStrings(groups)
+	require.Len(t, groups, 3)
+	require.Equal(t, map[string]any{"quantity": 1, "size": uint8(1)}, groups[0].Key)
+	require.Equal(t, "5/2", groups[0].Results["weight"])
+	require.Equal(t, "1/3", groups[0].Results["amount"])
+	require.Equal(t, "1/3", groups[0].Results["minAmount"])
+	require.Equal(t, map[string]any{"quantity": 2, "size": uint8(1)}, groups[2].Key)
+	require.Nil(t, groups[2].Results["amount"])
+	require.Nil(t, groups[2].Results["minAmount"])
+}
+
+func TestAggregateWithoutGroups(t *testing.T) {
+	groups, err := Aggregate([]TestObject{}, nil, nil, Count("count"), Avg("avg", "id"))
+	require.NoError(t, err)
+	require.Equal(t, []Group{{Key: map[string]any{}, Results: map[string]any{"count": 0, "avg": nil}}}, groups)
+
+	groups, err = Aggregate([]TestObject{}, nil, []string{"taskType"}, Count("count"))
+	require.NoError(t, err)
+	require.Empty(t, groups)
+}
+
+func TestAggregateErrors(t *testing.T) {
+	objects := []TestObject{{Id: 1, Name: "a"}, {Id: 2, HouseIds: []int{1}}}
+
+	_, err := Aggregate(objects, nil, nil, Sum("sum", ""))
+	require.EqualError(t, err, "sum aggregation 'sum' must have a field")
+
+	_, err = Aggregate(objects, nil, nil, Aggregation{Name: "median", Function: "median", Field: "id"})
+	require.EqualError(t, err, "unknown aggregate function: median")
+
+	_, err = Aggregate(objects, nil, nil, Sum("sum", "name"))
+	require.EqualError(t, err, "cannot aggregate field 'name': cannot sum values of type string")
+
+	_, err = Aggregate(objects, nil, nil, Max("max", "houseIds"))
+	require.EqualError(t, err, "cannot aggregate field 'houseIds': cannot compare variables of type slice and slice")
+
+	_, err = Aggregate(objects, nil, []string{"unknownField"}, Count("count"))
+	require.EqualError(t, err, "field 'unknownField' was not found on object")
+}
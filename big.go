@@ -0,0 +1,106 @@
+package filterobject
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+)
+
+var (
+	bigIntType   = reflect.TypeOf(big.Int{})
+	bigFloatType = reflect.TypeOf(big.Float{})
+	bigRatType   = reflect.TypeOf(big.Rat{})
+)
+
+// number is an exact representation of a numeric value. Infinite values have a nil rat
+// and the sign of the infinity in inf.
+type number struct {
+	rat *big.Rat
+	inf int
+}
+
+func (n number) cmp(other number) int {
+	if n.inf != 0 || other.inf != 0 {
+		return compareOrdered(int64(n.inf), int64(other.inf))
+	}
+	return n.rat.Cmp(other.rat)
+}
+
+func isBigNumber(v reflect.Value) bool {
+	t := v.Type()
+	return t == bigIntType || t == bigFloatType || t == bigRatType
+}
+
+func isNumber(v reflect.Value) bool {
+	return v.CanInt() || v.CanUint() || v.CanFloat() || isBigNumber(v)
+}
+
+// compareNumbers compares math/big values with each other, with native numbers and
+// with numeric strings without losing precision. It also compares native numbers of
+// different kinds, e.g. int and float64. The second return value is false if the values
+// are no such numbers.
+func compareNumbers(field, value reflect.Value) (int, bool, error) {
+	if !isBigNumber(field) && !isBigNumber(value) {
+		if !isNumber(field) || !isNumber(value) {
+			return 0, false, nil
+		}
+	}
+	a, ok := toNumber(field)
+	if !ok {
+		return 0, true, fmt.Errorf("cannot compare variables of type %s and %s", field.Type(), value.Type())
+	}
+	b, ok := toNumber(value)
+	if !ok {
+		return 0, true, fmt.Errorf("cannot compare variables of type %s and %s", field.Type(), value.Type())
+	}
+	return a.cmp(b), true, nil
+}
+
+// toNumber converts native numbers, math/big values and numeric strings into an exact number.
+func toNumber(v reflect.Value) (number, bool) {
+	switch {
+	case v.CanInt():
+		return number{rat: new(big.Rat).SetInt64(v.Int())}, true
+	case v.CanUint():
+		return number{rat: new(big.Rat).SetInt(new(big.Int).SetUint64(v.Uint()))}, true
+	case v.CanFloat():
+		f := v.Float()
+		if math.IsNaN(f) {
+			return number{}, false
+		}
+		if math.IsInf(f, 0) {
+			if f > 0 {
+				return number{inf: 1}, true
+			}
+			return number{inf: -1}, true
+		}
+		return number{rat: new(big.Rat).SetFloat64(f)}, true
+	case v.Kind() == reflect.String:
+		r, ok := new(big.Rat).SetString(v.String())
+		return number{rat: r}, ok
+	}
+	if !v.CanInterface() {
+		return number{}, false
+	}
+	if v.CanAddr() {
+		v = v.Addr()
+	} else {
+		ptr := reflect.New(v.Type())
+		ptr.Elem().Set(v)
+		v = ptr
+	}
+	switch n := v.Interface().(type) {
+	case *big.Int:
+		return number{rat: new(big.Rat).SetInt(n)}, true
+	case *big.Rat:
+		return number{rat: n}, true
+	case *big.Float:
+		if n.IsInf() {
+			return number{inf: n.Sign()}, true
+		}
+		r, _ := n.Rat(nil)
+		return number{rat: r}, true
+	}
+	return number{}, false
+}
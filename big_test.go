@@ -0,0 +1,135 @@
+package filterobject
+
+import (
+	"github.com/stretchr/testify/require"
+	"github.com/xafelium/filter"
+	"math"
+	"math/big"
+	"testing"
+)
+
+type BigTestObject struct {
+	Amount   *big.Rat
+	Total    *big.Int
+	Ratio    *big.Float
+	Quantity int
+	Weight   float64
+	Size     uint8
+}
+
+func TestBigNumbers(t *testing.T) {
+	var applies bool
+	var err error
+	amount, ok := new(big.Rat).SetString("1000.50")
+	require.True(t, ok)
+	total, ok := new(big.Int).SetString("123456789012345678901234567890", 10)
+	require.True(t, ok)
+	obj := BigTestObject{
+		Amount: amount,
+		Total:  total,
+		Ratio:  big.NewFloat(0.25),
+	}
+
+	// Numeric strings
+	applies, err = applyGreaterThan(obj, filter.GreaterThan("amount", "1000.49"))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyGreaterThan(obj, filter.GreaterThan("amount", "1000.50"))
+	require.NoError(t, err)
+	require.False(t, applies)
+
+	applies, err = applyEquals(obj, filter.Equals("amount", "1000.5"))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyGreaterThan(obj, filter.GreaterThan("total", "123456789012345678901234567889"))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	// Native numbers
+	applies, err = applyGreaterThanOrEqual(obj, filter.GreaterThanOrEqual("amount", 1000))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyLowerThan(obj, filter.LowerThan("amount", 1000.75))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyEquals(obj, filter.Equals("ratio", 0.25))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyLowerThan(obj, filter.LowerThan("total", math.Inf(1)))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyIn(obj, filter.In("ratio", []float64{0.1, 0.25}))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	// Big numbers with each other
+	applies, err = applyLowerThan(obj, filter.LowerThan("ratio", big.NewRat(1, 3)))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyEquals(obj, filter.Equals("ratio", big.NewRat(1, 4)))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyGreaterThan(obj, filter.GreaterThan("amount", big.NewInt(1000)))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyGreaterThan(obj, filter.GreaterThan("total", new(big.Float).SetInf(false)))
+	require.NoError(t, err)
+	require.False(t, applies)
+
+	// Nil values
+	applies, err = applyGreaterThan(BigTestObject{}, filter.GreaterThan("amount", "1"))
+	require.NoError(t, err)
+	require.False(t, applies)
+
+	// Errors
+	applies, err = applyGreaterThan(obj, filter.GreaterThan("amount", "a lot"))
+	require.Error(t, err)
+	require.False(t, applies)
+
+	applies, err = applyEquals(obj, filter.Equals("amount", "a lot"))
+	require.NoError(t, err)
+	require.False(t, applies)
+}
+
+func TestMixedNativeNumbers(t *testing.T) {
+	var applies bool
+	var err error
+	obj := BigTestObject{
+		Quantity: 3,
+		Weight:   2.5,
+		Size:     200,
+	}
+
+	applies, err = applyGreaterThan(obj, filter.GreaterThan("quantity", 2.5))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyEquals(obj, filter.Equals("quantity", 3.0))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyLowerThan(obj, filter.LowerThan("weight", 3))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyGreaterThan(obj, filter.GreaterThan("size", -1))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyIn(obj, filter.In("size", []int{100, 200}))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyGreaterThan(obj, filter.GreaterThan("quantity", math.NaN()))
+	require.Error(t, err)
+	require.False(t, applies)
+}
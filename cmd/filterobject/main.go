@@ -0,0 +1,350 @@
+// Command filterobject filters JSON records with the conditions of the filter package.
+//
+// Records are read from the files given as arguments or from stdin. The input may be
+// newline-delimited JSON with one record per line or a JSON array of records. Matching
+// records are written to stdout as newline-delimited JSON. Integers in records and conditions
+// are compared exactly, even if they cannot be represented as float64.
+//
+// The condition is given as JSON query expressions of the filter package with -filter,
+// e.g. '[{"field":"taskType","op":"=","value":"magic"}]', or in the expression syntax of
+// filterobject.ParseExpression with -where, e.g. 'taskType = "magic" AND id > 1'.
+// Several conditions are combined with AND.
+//
+// Usage:
+//
+//	filterobject [-filter json] [-where expression]... [-errors stop|skip|report] [-explain] [file...]
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/xafelium/filter"
+	"github.com/xafelium/filterobject"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+type expressions []string
+
+func (e *expressions) String() string {
+	return strings.Join(*e, " AND ")
+}
+
+func (e *expressions) Set(value string) error {
+	*e = append(*e, value)
+	return nil
+}
+
+type config struct {
+	condition filter.Condition
+	policy    filterobject.ErrorPolicy
+	explain   bool
+	files     []string
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	cfg, err := parseArgs(args, stderr)
+	if err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(stderr, err)
+		}
+		return 2
+	}
+	metrics := filterobject.NewMetrics()
+	opts := []filterobject.CompileOption{}
+	if cfg.explain {
+		opts = append(opts, filterobject.WithObserver(metrics))
+	}
+	compiled, err := filterobject.Compile(cfg.condition, opts...)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	if cfg.explain {
+		fmt.Fprintf(stderr, "condition: %s\n", compiled)
+	}
+
+	out := bufio.NewWriter(stdout)
+	defer out.Flush()
+	p := &processor{condition: compiled, policy: cfg.policy, out: out, stderr: stderr}
+	if len(cfg.files) == 0 {
+		cfg.files = []string{"-"}
+	}
+	for _, name := range cfg.files {
+		if err := p.processFile(name, stdin); err != nil {
+			out.Flush()
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+	}
+	if err := out.Flush(); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	if cfg.explain {
+		fmt.Fprintf(stderr, "records: %d read, %d matched, %d failed\n", p.read, p.matched, p.failed)
+		stats := metrics.Snapshot()
+		var types []string
+		for t := range stats {
+			types = append(types, t)
+		}
+		sort.Strings(types)
+		for _, t := range types {
+			s := stats[t]
+			fmt.Fprintf(stderr, "%s: %d evaluations, %d matches, %d errors, %s\n", t, s.Evaluations, s.Matches, s.Errors, s.Duration)
+		}
+	}
+	if p.failed > 0 && cfg.policy == filterobject.CollectErrors {
+		return 1
+	}
+	return 0
+}
+
+func parseArgs(args []string, stderr io.Writer) (*config, error) {
+	flags := flag.NewFlagSet("filterobject", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	var where expressions
+	filterJSON := flags.String("filter", "", "condition as JSON query expressions of the filter package")
+	flags.Var(&where, "where", "condition as expression like 'taskType = \"magic\" AND id > 1' (repeatable)")
+	errorMode := flags.String("errors", "stop", "handling of invalid records: stop, skip or report")
+	explain := flags.Bool("explain", false, "print the compiled condition and evaluation statistics to stderr")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	cfg := &config{explain: *explain, files: flags.Args()}
+	switch *errorMode {
+	case "stop":
+		cfg.policy = filterobject.StopOnError
+	case "skip":
+		cfg.policy = filterobject.SkipErrors
+	case "report":
+		cfg.policy = filterobject.CollectErrors
+	default:
+		return nil, fmt.Errorf("invalid error handling: %s", *errorMode)
+	}
+
+	var conditions []filter.Condition
+	if *filterJSON != "" {
+		var queryExpressions []*filter.QueryExpression
+		if err := decodeJSON([]byte(*filterJSON), &queryExpressions); err != nil {
+			return nil, fmt.Errorf("invalid filter: %w", err)
+		}
+		for _, expression := range queryExpressions {
+			if expression == nil {
+				continue
+			}
+			value, err := numbers(expression.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid filter: %w", err)
+			}
+			expression.Value = value
+		}
+		condition, err := filter.ParseQueryExpressions(queryExpressions)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, condition)
+	}
+	for _, expression := range where {
+		condition, err := filterobject.ParseExpression(expression)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, condition)
+	}
+	var nonEmpty []filter.Condition
+	for _, condition := range conditions {
+		if c := filter.UnwrapWhere(condition); c != nil {
+			nonEmpty = append(nonEmpty, c)
+		}
+	}
+	switch len(nonEmpty) {
+	case 0:
+		cfg.condition = filter.Where(nil)
+	case 1:
+		cfg.condition = filter.Where(nonEmpty[0])
+	default:
+		cfg.condition = filter.Where(filter.And(nonEmpty...))
+	}
+	return cfg, nil
+}
+
+type processor struct {
+	condition *filterobject.CompiledCondition
+	policy    filterobject.ErrorPolicy
+	out       io.Writer
+	stderr    io.Writer
+	read      int
+	matched   int
+	failed    int
+}
+
+func (p *processor) processFile(name string, stdin io.Reader) error {
+	var r io.Reader = stdin
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	} else {
+		name = "stdin"
+	}
+	reader := bufio.NewReader(r)
+	first, err := peekNonSpace(reader)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		return err
+	}
+	if first == '[' {
+		return p.processArray(name, reader)
+	}
+	for line := 1; ; line++ {
+		data, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(data)) > 0 {
+			if err := p.process(fmt.Sprintf("%s: line %d", name, line), data); err != nil {
+				return err
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// processArray processes the elements of the JSON array one by one, so the array is never
+// held in memory. Elements which are no valid records are handled according to the error
+// policy, while syntax errors of the array stop the processing.
+func (p *processor) processArray(name string, r io.Reader) error {
+	decoder := json.NewDecoder(r)
+	if _, err := decoder.Token(); err != nil {
+		return fmt.Errorf("%s: invalid JSON array: %w", name, err)
+	}
+	for i := 1; decoder.More(); i++ {
+		var record json.RawMessage
+		if err := decoder.Decode(&record); err != nil {
+			return fmt.Errorf("%s: invalid JSON array: %w", name, err)
+		}
+		if err := p.process(fmt.Sprintf("%s: record %d", name, i), record); err != nil {
+			return err
+		}
+	}
+	if _, err := decoder.Token(); err != nil {
+		return fmt.Errorf("%s: invalid JSON array: %w", name, err)
+	}
+	return nil
+}
+
+// process evaluates the record and writes it if the condition applies.
+// Invalid records are handled according to the error policy.
+func (p *processor) process(position string, data []byte) error {
+	p.read++
+	var record map[string]any
+	err := decodeJSON(data, &record)
+	applies := false
+	if err == nil {
+		var converted any
+		if converted, err = numbers(record); err == nil {
+			applies, err = p.condition.Applies(converted)
+		}
+	}
+	if err != nil {
+		p.failed++
+		switch p.policy {
+		case filterobject.SkipErrors:
+			return nil
+		case filterobject.CollectErrors:
+			fmt.Fprintf(p.stderr, "%s: %s\n", position, err)
+			return nil
+		default:
+			return fmt.Errorf("%s: %w", position, err)
+		}
+	}
+	if !applies {
+		return nil
+	}
+	p.matched++
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, data); err != nil {
+		return err
+	}
+	compact.WriteByte('\n')
+	_, err = p.out.Write(compact.Bytes())
+	return err
+}
+
+// decodeJSON decodes the JSON value like json.Unmarshal but keeps numbers as json.Number,
+// so they can be converted by numbers without losing precision.
+func decodeJSON(data []byte, v any) error {
+	var raw json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+// numbers replaces the json.Number values within the decoded value by int64 or uint64 values
+// if they are integers which fit into these types and by float64 values otherwise.
+// Numbers which are out of the range of float64 result in an error.
+func numbers(v any) (any, error) {
+	var err error
+	switch value := v.(type) {
+	case json.Number:
+		if i, err := strconv.ParseInt(value.String(), 10, 64); err == nil {
+			return i, nil
+		}
+		if u, err := strconv.ParseUint(value.String(), 10, 64); err == nil {
+			return u, nil
+		}
+		f, err := strconv.ParseFloat(value.String(), 64)
+		if err != nil {
+			return nil, fmt.Errorf("number %s is out of range", value)
+		}
+		return f, nil
+	case map[string]any:
+		for key, entry := range value {
+			if value[key], err = numbers(entry); err != nil {
+				return nil, err
+			}
+		}
+	case []any:
+		for i, element := range value {
+			if value[i], err = numbers(element); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return v, nil
+}
+
+func peekNonSpace(r *bufio.Reader) (byte, error) {
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if b != ' ' && b != '\t' && b != '\r' && b != '\n' {
+			return b, r.UnreadByte()
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const records = `{"id": 1, "taskType": "magic", "nicknames": ["a"]}
+{"id": 2, "taskType": "chore"}
+
+{"id": 3, "taskType": "magic", "nicknames": ["b"], "labels": {"env": "prod"}}
+`
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		input    string
+		expected string
+		exitCode int
+		stderr   string
+	}{
+		{
+			name:     "no condition",
+			input:    records,
+			expected: "{\"id\":1,\"taskType\":\"magic\",\"nicknames\":[\"a\"]}\n{\"id\":2,\"taskType\":\"chore\"}\n{\"id\":3,\"taskType\":\"magic\",\"nicknames\":[\"b\"],\"labels\":{\"env\":\"prod\"}}\n",
+		},
+		{
+			name:     "where expressions",
+			args:     []string{"-where", `taskType = "magic"`, "-where", "id > 1"},
+			input:    records,
+			expected: "{\"id\":3,\"taskType\":\"magic\",\"nicknames\":[\"b\"],\"labels\":{\"env\":\"prod\"}}\n",
+		},
+		{
+			name:     "where expression with list and nested field",
+			args:     []string{"-where", `id in [1, 3] AND labels.env IS NULL`},
+			input:    records,
+			expected: "{\"id\":1,\"taskType\":\"magic\",\"nicknames\":[\"a\"]}\n",
+		},
+		{
+			name:     "filter",
+			args:     []string{"-filter", `[{"field":"nicknames","op":"arrayContains","value":"b"}]`, "-where", "id >= 3"},
+			input:    records,
+			expected: "{\"id\":3,\"taskType\":\"magic\",\"nicknames\":[\"b\"],\"labels\":{\"env\":\"prod\"}}\n",
+		},
+		{
+			name:     "integers above 2^53",
+			args:     []string{"-where", "id = 9007199254740993"},
+			input:    "{\"id\": 9007199254740992}\n{\"id\": 9007199254740993}\n{\"id\": 18446744073709551615}\n",
+			expected: "{\"id\":9007199254740993}\n",
+		},
+		{
+			name:     "filter with integers above 2^53",
+			args:     []string{"-filter", `[{"field":"id","op":">","value":9007199254740992}]`},
+			input:    "{\"id\": 9007199254740992}\n{\"id\": 9007199254740993}\n{\"id\": 18446744073709551615}\n{\"id\": 1.5}\n",
+			expected: "{\"id\":9007199254740993}\n{\"id\":18446744073709551615}\n",
+		},
+		{
+			name:     "array input",
+			args:     []string{"-where", "id <= 1"},
+			input:    ` [{"id": 1}, {"id": 2}]`,
+			expected: "{\"id\":1}\n",
+		},
+		{
+			name:     "skip invalid array elements",
+			args:     []string{"-where", "id >= 1", "-errors", "skip"},
+			input:    `[{"id": 1}, [1], "x", {"id": 2}]`,
+			expected: "{\"id\":1}\n{\"id\":2}\n",
+		},
+		{
+			name:     "invalid array",
+			args:     []string{"-where", "id >= 1", "-errors", "skip"},
+			input:    `[{"id": 1}, {"id": 2} {"id": 3}]`,
+			expected: "{\"id\":1}\n{\"id\":2}\n",
+			exitCode: 1,
+			stderr:   "stdin: invalid JSON array: invalid character '{' after array element\n",
+		},
+		{
+			name:     "number out of range",
+			args:     []string{"-where", "id >= 1", "-errors", "report"},
+			input:    "{\"id\": 1}\n{\"id\": 1e400}\n{\"id\": 3}\n",
+			expected: "{\"id\":1}\n{\"id\":3}\n",
+			exitCode: 1,
+			stderr:   "stdin: line 2: number 1e400 is out of range\n",
+		},
+		{
+			name:     "filter with number out of range",
+			args:     []string{"-filter", `[{"field":"id","op":">","value":-1e400}]`},
+			exitCode: 2,
+			stderr:   "invalid filter: number -1e400 is out of range\n",
+		},
+		{
+			name:     "stop on invalid record",
+			args:     []string{"-where", "id >= 1"},
+			input:    "{\"id\": 1}\n{\"id\": \n{\"id\": 3}\n",
+			expected: "{\"id\":1}\n",
+			exitCode: 1,
+			stderr:   "stdin: line 2: unexpected end of JSON input\n",
+		},
+		{
+			name:     "skip invalid records",
+			args:     []string{"-where", "id >= 1", "-errors", "skip"},
+			input:    "{\"id\": 1}\n{\"id\": \n[1]\n{\"id\": 3}\n",
+			expected: "{\"id\":1}\n{\"id\":3}\n",
+		},
+		{
+			name:     "report invalid records",
+			args:     []string{"-where", "id >= 1", "-errors", "report"},
+			input:    "{\"id\": 1}\n{\"id\": \n{\"id\": 3}\n",
+			expected: "{\"id\":1}\n{\"id\":3}\n",
+			exitCode: 1,
+			stderr:   "stdin: line 2: unexpected end of JSON input\n",
+		},
+		{
+			name:     "explain",
+			args:     []string{"-where", `taskType = "chore"`, "-explain"},
+			input:    records,
+			expected: "{\"id\":2,\"taskType\":\"chore\"}\n",
+			stderr:   "condition: taskType = chore\nrecords: 3 read, 1 matched, 0 failed\nEqualsCondition: 3 evaluations, 1 matches, 0 errors",
+		},
+		{
+			name:     "invalid expression",
+			args:     []string{"-where", "id"},
+			exitCode: 2,
+			stderr:   "syntax error at line 1, column 3: expected operator but found end of input\n",
+		},
+		{
+			name:     "unknown operator",
+			args:     []string{"-filter", `[{"field":"id","op":"~","value":1}]`},
+			exitCode: 2,
+			stderr:   "unknown operator: ~\n",
+		},
+		{
+			name:     "invalid error handling",
+			args:     []string{"-errors", "ignore"},
+			exitCode: 2,
+			stderr:   "invalid error handling: ignore\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			exitCode := run(test.args, strings.NewReader(test.input), &stdout, &stderr)
+			require.Equal(t, test.exitCode, exitCode, stderr.String())
+			require.Equal(t, test.expected, stdout.String())
+			if test.stderr == "" {
+				require.Empty(t, stderr.String())
+			} else {
+				require.Contains(t, stderr.String(), test.stderr)
+			}
+		})
+	}
+}
+
+func TestRunFiles(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.ndjson")
+	second := filepath.Join(dir, "second.json")
+	require.NoError(t, os.WriteFile(first, []byte(records), 0o600))
+	require.NoError(t, os.WriteFile(second, []byte(`[{"id": 4, "taskType": "magic"}]`), 0o600))
+
+	var stdout, stderr bytes.Buffer
+	exitCode := run([]string{"-where", `taskType = "magic"`, first, second}, strings.NewReader(""), &stdout, &stderr)
+	require.Equal(t, 0, exitCode, stderr.String())
+	require.Equal(t, 3, strings.Count(stdout.String(), "\n"))
+	require.Contains(t, stdout.String(), `{"id":4,"taskType":"magic"}`)
+
+	stdout.Reset()
+	exitCode = run([]string{filepath.Join(dir, "missing.json")}, strings.NewReader(""), &stdout, &stderr)
+	require.Equal(t, 1, exitCode)
+	require.Contains(t, stderr.String(), "no such file or directory")
+}
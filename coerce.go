@@ -0,0 +1,393 @@
+package filterobject
+
+import (
+	"encoding"
+	"fmt"
+	"github.com/xafelium/filter"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var (
+	stringerType        = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// CoerceCondition returns a copy of the condition whose operands are converted into the types
+// of the fields of objects of type t, e.g. the float64 and []any operands of decoded conditions
+// into the int and []string types of the fields. Operands which cannot be converted without
+// losing information result in an error. Operands without a conversion rule are kept as they are.
+func CoerceCondition(condition filter.Condition, t reflect.Type) (filter.Condition, error) {
+	if condition == nil {
+		return nil, nil
+	}
+	switch c := condition.(type) {
+	case *filter.WhereCondition:
+		inner, err := CoerceCondition(c.Condition, t)
+		if err != nil {
+			return nil, err
+		}
+		return filter.Where(inner), nil
+	case *filter.GroupCondition:
+		inner, err := CoerceCondition(c.Condition, t)
+		if err != nil {
+			return nil, err
+		}
+		return filter.Group(inner), nil
+	case *filter.NotCondition:
+		inner, err := CoerceCondition(c.Condition, t)
+		if err != nil {
+			return nil, err
+		}
+		return filter.Not(inner), nil
+	case *filter.AndCondition:
+		conditions, err := coerceConditions(c.Conditions, t)
+		if err != nil {
+			return nil, err
+		}
+		return filter.And(conditions...), nil
+	case *filter.OrCondition:
+		conditions, err := coerceConditions(c.Conditions, t)
+		if err != nil {
+			return nil, err
+		}
+		return filter.Or(conditions...), nil
+	}
+	coercion, ok := leafCoercionOf(condition)
+	if !ok {
+		return condition, nil
+	}
+	ft, err := fieldType(t, coercion.field)
+	if err != nil {
+		return nil, err
+	}
+	return coercion.coerce(ft)
+}
+
+func coerceConditions(conditions []filter.Condition, t reflect.Type) ([]filter.Condition, error) {
+	coerced := make([]filter.Condition, 0, len(conditions))
+	for _, c := range conditions {
+		cc, err := CoerceCondition(c, t)
+		if err != nil {
+			return nil, err
+		}
+		coerced = append(coerced, cc)
+	}
+	return coerced, nil
+}
+
+// leafCoercion converts the operand of a leaf condition into the type derived from the type
+// of its field by operandType.
+type leafCoercion struct {
+	field       string
+	value       any
+	operandType func(reflect.Type) reflect.Type
+	create      func(field string, value any) filter.Condition
+}
+
+// leafCoercionOf returns the coercion of the condition. The second return value is false if
+// the operand of the condition is not coerced.
+func leafCoercionOf(condition filter.Condition) (*leafCoercion, bool) {
+	switch c := condition.(type) {
+	case *filter.EqualsCondition:
+		return &leafCoercion{c.Field, c.Value, fieldValueType, filter.Equals}, true
+	case *filter.NotEqualsCondition:
+		return &leafCoercion{c.Field, c.Value, fieldValueType, filter.NotEquals}, true
+	case *filter.GreaterThanCondition:
+		return &leafCoercion{c.Field, c.Value, fieldValueType, filter.GreaterThan}, true
+	case *filter.GreaterThanOrEqualCondition:
+		return &leafCoercion{c.Field, c.Value, fieldValueType, filter.GreaterThanOrEqual}, true
+	case *filter.LowerThanCondition:
+		return &leafCoercion{c.Field, c.Value, fieldValueType, filter.LowerThan}, true
+	case *filter.LowerThanOrEqualCondition:
+		return &leafCoercion{c.Field, c.Value, fieldValueType, filter.LowerThanOrEqual}, true
+	case *filter.InCondition:
+		return &leafCoercion{c.Field, c.Value, fieldListType, filter.In}, true
+	case *filter.ArrayContainsCondition:
+		return &leafCoercion{c.Field, c.Value, fieldElemType, filter.ArrayContains}, true
+	case *filter.ArrayContainsArrayCondition:
+		return &leafCoercion{c.Field, c.Value, fieldSliceType, filter.ArrayContainsArray}, true
+	case *filter.ArrayIsContainedCondition:
+		return &leafCoercion{c.Field, c.Value, fieldSliceType, filter.ArrayIsContained}, true
+	case *filter.ArraysOverlapCondition:
+		return &leafCoercion{c.Field, c.Value, fieldSliceType, filter.ArraysOverlap}, true
+	case *filter.OverlapsCondition:
+		return &leafCoercion{c.Field, c.Value, fieldSliceType, filter.Overlaps}, true
+	}
+	return nil, false
+}
+
+// coerce creates the condition with the operand converted for a field of type ft.
+func (c *leafCoercion) coerce(ft reflect.Type) (filter.Condition, error) {
+	target := c.operandType(ft)
+	if target == nil {
+		return c.create(c.field, c.value), nil
+	}
+	v, err := coerceValue(reflect.ValueOf(c.value), target)
+	if err != nil {
+		return nil, fmt.Errorf("cannot coerce value of field '%s': %w", c.field, err)
+	}
+	if !v.IsValid() {
+		return c.create(c.field, nil), nil
+	}
+	return c.create(c.field, v.Interface()), nil
+}
+
+// fieldValueType returns the type of operands compared with the field itself.
+func fieldValueType(t reflect.Type) reflect.Type {
+	return t
+}
+
+// fieldListType returns the type of lists of operands compared with the field itself.
+func fieldListType(t reflect.Type) reflect.Type {
+	return reflect.SliceOf(t)
+}
+
+// fieldElemType returns the type of operands compared with the elements of slice fields.
+func fieldElemType(t reflect.Type) reflect.Type {
+	t = derefType(t)
+	if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+		return nil
+	}
+	return t.Elem()
+}
+
+// fieldSliceType returns the type of lists of operands compared with the elements of slice fields.
+func fieldSliceType(t reflect.Type) reflect.Type {
+	elem := fieldElemType(t)
+	if elem == nil {
+		return nil
+	}
+	return reflect.SliceOf(elem)
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// coerceValue converts v into the type t. Pointer types are converted into their element
+// types. Strings are parsed as RFC 3339 times, durations, booleans and numbers or with
+// encoding.TextUnmarshaler, numbers are converted into other numeric types and slices are
+// converted element by element if all elements can be converted; nil elements are an error
+// if the element type has no nil value. Strings which are no
+// RFC 3339 time are kept for time fields, since they may be dates, clock times or relative
+// times. Values without a conversion rule are returned unchanged.
+func coerceValue(v reflect.Value, t reflect.Type) (reflect.Value, error) {
+	v = indirect(v)
+	t = derefType(t)
+	if !v.IsValid() || v.Type() == t || t.Kind() == reflect.Interface {
+		return v, nil
+	}
+	switch t {
+	case timeType:
+		if v.Kind() != reflect.String {
+			return v, nil
+		}
+		parsed, err := time.Parse(time.RFC3339Nano, v.String())
+		if err != nil {
+			return v, nil
+		}
+		return reflect.ValueOf(parsed), nil
+	case durationType:
+		if v.Kind() != reflect.String {
+			break
+		}
+		d, err := time.ParseDuration(v.String())
+		if err != nil {
+			return v, fmt.Errorf("invalid duration %q: %w", v.String(), err)
+		}
+		return reflect.ValueOf(d), nil
+	}
+	if v.Kind() == reflect.String && reflect.PtrTo(t).Implements(textUnmarshalerType) {
+		p := reflect.New(t)
+		if err := p.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(v.String())); err != nil {
+			return v, fmt.Errorf("cannot convert %q into %s: %w", v.String(), t, err)
+		}
+		return p.Elem(), nil
+	}
+	if v.Kind() == reflect.String && t.Kind() != reflect.String && hasStringRepresentation(t) {
+		// Strings are compared with the string representation of such fields or, for
+		// enums, with the names of the members.
+		if e, ok := lookupEnum(t); ok {
+			if member, ok := e.member(v.String()); ok {
+				return member, nil
+			}
+		}
+		return v, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		switch v.Kind() {
+		case reflect.Bool:
+			return v.Convert(t), nil
+		case reflect.String:
+			b, err := strconv.ParseBool(v.String())
+			if err != nil {
+				return v, fmt.Errorf("cannot convert %q into %s", v.String(), t)
+			}
+			return reflect.ValueOf(b).Convert(t), nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return coerceInt(v, t)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return coerceUint(v, t)
+	case reflect.Float32, reflect.Float64:
+		return coerceFloat(v, t)
+	case reflect.String:
+		if v.Kind() == reflect.String {
+			return v.Convert(t), nil
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			break
+		}
+		if t.Kind() == reflect.Array && v.Len() != t.Len() {
+			return v, fmt.Errorf("cannot convert %d values into %s", v.Len(), t)
+		}
+		var result reflect.Value
+		if t.Kind() == reflect.Slice {
+			result = reflect.MakeSlice(t, v.Len(), v.Len())
+		} else {
+			result = reflect.New(t).Elem()
+		}
+		for i := 0; i < v.Len(); i++ {
+			elem, err := coerceValue(v.Index(i), t.Elem())
+			if err != nil {
+				return v, err
+			}
+			if !elem.IsValid() {
+				switch t.Elem().Kind() {
+				case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+					continue
+				}
+				return v, fmt.Errorf("cannot convert nil at index %d into %s", i, t.Elem())
+			}
+			if t.Elem().Kind() == reflect.Ptr && elem.Type().AssignableTo(t.Elem().Elem()) {
+				p := reflect.New(t.Elem().Elem())
+				p.Elem().Set(elem)
+				elem = p
+			}
+			if !elem.Type().AssignableTo(t.Elem()) {
+				// Lists are only converted if all of their values can be converted.
+				return v, nil
+			}
+			result.Index(i).Set(elem)
+		}
+		return result, nil
+	}
+	return v, nil
+}
+
+func hasStringRepresentation(t reflect.Type) bool {
+	p := reflect.PtrTo(t)
+	return p.Implements(stringerType) || p.Implements(textMarshalerType)
+}
+
+func coerceInt(v reflect.Value, t reflect.Type) (reflect.Value, error) {
+	var i int64
+	switch {
+	case v.CanInt():
+		i = v.Int()
+	case v.CanUint():
+		if v.Uint() > math.MaxInt64 {
+			return v, lossError(v, t)
+		}
+		i = int64(v.Uint())
+	case v.CanFloat():
+		f := v.Float()
+		if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+			return v, lossError(v, t)
+		}
+		i = int64(f)
+	case v.Kind() == reflect.String:
+		parsed, err := strconv.ParseInt(v.String(), 10, 64)
+		if err != nil {
+			return v, fmt.Errorf("cannot convert %q into %s", v.String(), t)
+		}
+		i = parsed
+	default:
+		return v, nil
+	}
+	result := reflect.New(t).Elem()
+	if result.OverflowInt(i) {
+		return v, lossError(v, t)
+	}
+	result.SetInt(i)
+	return result, nil
+}
+
+func coerceUint(v reflect.Value, t reflect.Type) (reflect.Value, error) {
+	var u uint64
+	switch {
+	case v.CanUint():
+		u = v.Uint()
+	case v.CanInt():
+		if v.Int() < 0 {
+			return v, lossError(v, t)
+		}
+		u = uint64(v.Int())
+	case v.CanFloat():
+		f := v.Float()
+		if f != math.Trunc(f) || f < 0 || f >= math.MaxUint64 {
+			return v, lossError(v, t)
+		}
+		u = uint64(f)
+	case v.Kind() == reflect.String:
+		parsed, err := strconv.ParseUint(v.String(), 10, 64)
+		if err != nil {
+			return v, fmt.Errorf("cannot convert %q into %s", v.String(), t)
+		}
+		u = parsed
+	default:
+		return v, nil
+	}
+	result := reflect.New(t).Elem()
+	if result.OverflowUint(u) {
+		return v, lossError(v, t)
+	}
+	result.SetUint(u)
+	return result, nil
+}
+
+func coerceFloat(v reflect.Value, t reflect.Type) (reflect.Value, error) {
+	var f float64
+	switch {
+	case v.CanFloat():
+		f = v.Float()
+	case v.CanInt():
+		f = float64(v.Int())
+		if f >= math.MaxInt64 || int64(f) != v.Int() {
+			return v, lossError(v, t)
+		}
+	case v.CanUint():
+		f = float64(v.Uint())
+		if f >= math.MaxUint64 || uint64(f) != v.Uint() {
+			return v, lossError(v, t)
+		}
+	case v.Kind() == reflect.String:
+		parsed, err := strconv.ParseFloat(v.String(), 64)
+		if err != nil {
+			return v, fmt.Errorf("cannot convert %q into %s", v.String(), t)
+		}
+		f = parsed
+	default:
+		return v, nil
+	}
+	if t.Kind() == reflect.Float32 && !math.IsNaN(f) && float64(float32(f)) != f {
+		return v, lossError(v, t)
+	}
+	result := reflect.New(t).Elem()
+	result.SetFloat(f)
+	return result, nil
+}
+
+func lossError(v reflect.Value, t reflect.Type) error {
+	return fmt.Errorf("cannot convert %v into %s without losing information", v.Interface(), t)
+}
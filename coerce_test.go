@@ -0,0 +1,81 @@
+package filterobject
+
+import (
+	"github.com/stretchr/testify/require"
+	"github.com/xafelium/filter"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type CoerceTestObject struct {
+	Count    int8
+	Size     uint16
+	Ratio    float32
+	Enabled  *bool
+	Timeout  time.Duration
+	Level    Level
+	Priority Priority
+	Tags     []State
+	Slots    [2]int
+	Amount   *big.Rat
+	Labels   map[string]any
+}
+
+func TestCoerceCondition(t *testing.T) {
+	trueValue := true
+	tests := []struct {
+		condition filter.Condition
+		expected  filter.Condition
+	}{
+		{condition: filter.Equals("count", 3.0), expected: filter.Equals("count", int8(3))},
+		{condition: filter.Equals("count", "-3"), expected: filter.Equals("count", int8(-3))},
+		{condition: filter.GreaterThan("size", 3), expected: filter.GreaterThan("size", uint16(3))},
+		{condition: filter.LowerThan("ratio", 1), expected: filter.LowerThan("ratio", float32(1))},
+		{condition: filter.Equals("enabled", "true"), expected: filter.Equals("enabled", true)},
+		{condition: filter.Equals("enabled", nil), expected: filter.Equals("enabled", nil)},
+		{condition: filter.LowerThan("timeout", "1m"), expected: filter.LowerThan("timeout", time.Minute)},
+		{condition: filter.LowerThan("timeout", 1000), expected: filter.LowerThan("timeout", time.Microsecond)},
+		{condition: filter.Equals("level", 2), expected: filter.Equals("level", LevelError)},
+		{condition: filter.Equals("level", "info"), expected: filter.Equals("level", LevelInfo)},
+		{condition: filter.Equals("priority", "high"), expected: filter.Equals("priority", PriorityHigh)},
+		{condition: filter.In("priority", []any{"low", "high"}), expected: filter.In("priority", []Priority{PriorityLow, PriorityHigh})},
+		{condition: filter.ArrayContains("tags", "open"), expected: filter.ArrayContains("tags", State("open"))},
+		{condition: filter.ArraysOverlap("slots", []any{1.0}), expected: filter.ArraysOverlap("slots", []int{1})},
+		{condition: filter.ArrayIsContained("tags", []any{"a", 1}), expected: filter.ArrayIsContained("tags", []any{"a", 1})},
+		{condition: filter.In("count", []any{1, true}), expected: filter.In("count", []any{1, true})},
+		{condition: filter.In("enabled", []any{"true", nil}), expected: filter.In("enabled", []*bool{&trueValue, nil})},
+		{condition: filter.Equals("amount", 1.5), expected: filter.Equals("amount", 1.5)},
+		{condition: filter.Equals("labels.env", 1.0), expected: filter.Equals("labels.env", 1.0)},
+		{condition: filter.Contains("priority", "h"), expected: filter.Contains("priority", "h")},
+		{
+			condition: filter.Where(filter.Or(filter.Not(filter.Equals("count", 1.0)), filter.Group(filter.Equals("size", 2.0)))),
+			expected:  filter.Where(filter.Or(filter.Not(filter.Equals("count", int8(1))), filter.Group(filter.Equals("size", uint16(2))))),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.condition.String(), func(t *testing.T) {
+			condition, err := CoerceCondition(test.condition, reflect.TypeOf(CoerceTestObject{}))
+			require.NoError(t, err)
+			require.Equal(t, test.expected, condition)
+		})
+	}
+}
+
+func TestCoerceConditionErrors(t *testing.T) {
+	for condition, expected := range map[filter.Condition]string{
+		filter.Equals("count", 300):               "cannot coerce value of field 'count': cannot convert 300 into int8 without losing information",
+		filter.Equals("count", 1.5):               "cannot coerce value of field 'count': cannot convert 1.5 into int8 without losing information",
+		filter.Equals("count", "x"):               "cannot coerce value of field 'count': cannot convert \"x\" into int8",
+		filter.Equals("size", -1):                 "cannot coerce value of field 'size': cannot convert -1 into uint16 without losing information",
+		filter.Equals("ratio", 0.1):               "cannot coerce value of field 'ratio': cannot convert 0.1 into float32 without losing information",
+		filter.Equals("enabled", "yes"):           "cannot coerce value of field 'enabled': cannot convert \"yes\" into bool",
+		filter.Equals("timeout", "soon"):          "cannot coerce value of field 'timeout': invalid duration \"soon\": time: invalid duration \"soon\"",
+		filter.ArraysOverlap("slots", []any{"a"}): "cannot coerce value of field 'slots': cannot convert \"a\" into int",
+		filter.In("count", []any{1, nil}):         "cannot coerce value of field 'count': cannot convert nil at index 1 into int8",
+	} {
+		_, err := CoerceCondition(condition, reflect.TypeOf(CoerceTestObject{}))
+		require.EqualError(t, err, expected, condition.String())
+	}
+}
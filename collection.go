@@ -0,0 +1,807 @@
+package filterobject
+
+import (
+	"fmt"
+	"github.com/xafelium/filter"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type indexKind int
+
+const (
+	hashIndexKind indexKind = iota
+	sortedIndexKind
+	invertedIndexKind
+)
+
+func (k indexKind) String() string {
+	switch k {
+	case hashIndexKind:
+		return "hash"
+	case sortedIndexKind:
+		return "sorted"
+	default:
+		return "inverted"
+	}
+}
+
+type indexDefinition struct {
+	kind  indexKind
+	field string
+}
+
+// IndexOption defines an index of a Collection.
+type IndexOption func(definitions *[]indexDefinition)
+
+// WithHashIndex creates a hash index on the field, which is used for Equals and In conditions.
+func WithHashIndex(field string) IndexOption {
+	return func(definitions *[]indexDefinition) {
+		*definitions = append(*definitions, indexDefinition{kind: hashIndexKind, field: field})
+	}
+}
+
+// WithSortedIndex creates a sorted index on the field, which is used for GreaterThan,
+// GreaterThanOrEqual, LowerThan and LowerThanOrEqual conditions.
+func WithSortedIndex(field string) IndexOption {
+	return func(definitions *[]indexDefinition) {
+		*definitions = append(*definitions, indexDefinition{kind: sortedIndexKind, field: field})
+	}
+}
+
+// WithInvertedIndex creates an inverted index on a slice or array field, which is used for
+// ArrayContains, ArrayContainsArray, ArraysOverlap and Overlaps conditions.
+func WithInvertedIndex(field string) IndexOption {
+	return func(definitions *[]indexDefinition) {
+		*definitions = append(*definitions, indexDefinition{kind: invertedIndexKind, field: field})
+	}
+}
+
+// Collection is an in-memory collection of objects with secondary indexes.
+// Queries use the indexes for the indexable parts of a condition to find candidates and
+// evaluate the whole condition only for these candidates.
+// Indexes can be created on fields of type string, bool, time.Time and on numeric fields
+// including math/big values. Fields with wildcard segments like "items[*].sku" cannot be indexed.
+//
+// A Collection is safe for concurrent use.
+type Collection[T any] struct {
+	mutex sync.RWMutex
+	items []T
+	collectionIndexes
+}
+
+type collectionIndexes struct {
+	hash     map[string]*hashIndex
+	sorted   map[string]*sortedIndex
+	inverted map[string]*hashIndex
+}
+
+// NewCollection creates a new Collection containing the items.
+func NewCollection[T any](items []T, indexes ...IndexOption) (*Collection[T], error) {
+	var definitions []indexDefinition
+	for _, index := range indexes {
+		index(&definitions)
+	}
+	c := &Collection[T]{
+		collectionIndexes: collectionIndexes{
+			hash:     make(map[string]*hashIndex),
+			sorted:   make(map[string]*sortedIndex),
+			inverted: make(map[string]*hashIndex),
+		},
+	}
+	itemType := reflect.TypeOf((*T)(nil)).Elem()
+	for _, definition := range definitions {
+		if isMultiValued(definition.field) {
+			return nil, fmt.Errorf("field '%s' has multiple values and cannot be used for a %s index", definition.field, definition.kind)
+		}
+		t, err := fieldType(itemType, definition.field)
+		if err != nil {
+			return nil, err
+		}
+		if definition.kind == invertedIndexKind {
+			if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+				return nil, fmt.Errorf("field '%s' must be of type slice/array for an inverted index but is of type %s", definition.field, t)
+			}
+			t = t.Elem()
+		}
+		category := indexCategoryOf(t)
+		if category == noCategory || (definition.kind == sortedIndexKind && category == boolCategory) {
+			return nil, fmt.Errorf("field '%s' of type %s cannot be used for a %s index", definition.field, t, definition.kind)
+		}
+		switch definition.kind {
+		case hashIndexKind:
+			c.hash[definition.field] = newHashIndex(category)
+		case sortedIndexKind:
+			c.sorted[definition.field] = &sortedIndex{category: category}
+		case invertedIndexKind:
+			c.inverted[definition.field] = newHashIndex(category)
+		}
+	}
+	if err := c.load(items); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Len returns the number of items in the collection.
+func (c *Collection[T]) Len() int {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return len(c.items)
+}
+
+// Add adds the item to the collection and updates the indexes.
+func (c *Collection[T]) Add(item T) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.add(item)
+}
+
+func (c *Collection[T]) add(item T) error {
+	if err := c.collectionIndexes.add(item, len(c.items), false); err != nil {
+		return err
+	}
+	c.items = append(c.items, item)
+	return nil
+}
+
+// load replaces the items of the collection and rebuilds the indexes. The indexes are built
+// into new structures and sorted indexes are sorted once after all items were added, so the
+// collection is unchanged if an item cannot be indexed.
+func (c *Collection[T]) load(items []T) error {
+	indexes := c.collectionIndexes.empty()
+	for position, item := range items {
+		if err := indexes.add(item, position, true); err != nil {
+			return err
+		}
+	}
+	for _, index := range indexes.sorted {
+		if err := index.sort(); err != nil {
+			return err
+		}
+	}
+	c.items = append([]T(nil), items...)
+	c.collectionIndexes = indexes
+	return nil
+}
+
+// empty returns indexes on the same fields without entries.
+func (c collectionIndexes) empty() collectionIndexes {
+	indexes := collectionIndexes{
+		hash:     make(map[string]*hashIndex, len(c.hash)),
+		sorted:   make(map[string]*sortedIndex, len(c.sorted)),
+		inverted: make(map[string]*hashIndex, len(c.inverted)),
+	}
+	for field, index := range c.hash {
+		indexes.hash[field] = newHashIndex(index.category)
+	}
+	for field, index := range c.sorted {
+		indexes.sorted[field] = &sortedIndex{category: index.category}
+	}
+	for field, index := range c.inverted {
+		indexes.inverted[field] = newHashIndex(index.category)
+	}
+	return indexes
+}
+
+// add adds the item at the position to the indexes. If unsorted is true, the entries of sorted
+// indexes are appended and must be sorted afterwards. The indexes are only changed if the item
+// can be added to all of them.
+func (c collectionIndexes) add(item any, position int, unsorted bool) error {
+	var updates []func()
+	for field, index := range c.hash {
+		value, err := getField(item, field)
+		if err != nil {
+			return err
+		}
+		index := index
+		updates = append(updates, func() {
+			index.add(value, position)
+		})
+	}
+	for field, index := range c.inverted {
+		value, err := getField(item, field)
+		if err != nil {
+			return err
+		}
+		value = indirect(value)
+		if !value.IsValid() {
+			continue
+		}
+		index := index
+		updates = append(updates, func() {
+			for i := 0; i < value.Len(); i++ {
+				index.add(value.Index(i), position)
+			}
+		})
+	}
+	for field, index := range c.sorted {
+		value, err := getField(item, field)
+		if err != nil {
+			return err
+		}
+		value, ok := sortedValue(value)
+		if !ok {
+			continue
+		}
+		index := index
+		if unsorted {
+			updates = append(updates, func() {
+				index.append(value, position)
+			})
+			continue
+		}
+		at, err := index.search(value)
+		if err != nil {
+			return err
+		}
+		updates = append(updates, func() {
+			index.insert(at, value, position)
+		})
+	}
+	for _, update := range updates {
+		update()
+	}
+	return nil
+}
+
+// Remove removes all items the condition applies to and returns the number of removed items.
+// The indexes are rebuilt afterwards. If they cannot be rebuilt, no items are removed.
+func (c *Collection[T]) Remove(condition filter.Condition) (int, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	matches, err := c.query(condition)
+	if err != nil {
+		return 0, err
+	}
+	if len(matches) == 0 {
+		return 0, nil
+	}
+	items := make([]T, 0, len(c.items)-len(matches))
+	next := 0
+	for position, item := range c.items {
+		if next < len(matches) && matches[next] == position {
+			next++
+			continue
+		}
+		items = append(items, item)
+	}
+	if err = c.load(items); err != nil {
+		return 0, err
+	}
+	return len(matches), nil
+}
+
+// Query returns all items the condition applies to in the order they were added.
+func (c *Collection[T]) Query(condition filter.Condition) ([]T, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	positions, err := c.query(condition)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]T, 0, len(positions))
+	for _, position := range positions {
+		result = append(result, c.items[position])
+	}
+	return result, nil
+}
+
+func (c *Collection[T]) query(condition filter.Condition) ([]int, error) {
+	compiled, err := Compile(condition)
+	if err != nil {
+		return nil, err
+	}
+	candidates := c.plan(condition).candidates
+	var positions []int
+	check := func(position int) error {
+		applies, err := compiled.Applies(c.items[position])
+		if err != nil {
+			return err
+		}
+		if applies {
+			positions = append(positions, position)
+		}
+		return nil
+	}
+	if candidates == nil {
+		for position := range c.items {
+			if err = check(position); err != nil {
+				return nil, err
+			}
+		}
+		return positions, nil
+	}
+	for _, position := range candidates {
+		if err = check(position); err != nil {
+			return nil, err
+		}
+	}
+	return positions, nil
+}
+
+// Explain describes how the condition is evaluated by Query.
+func (c *Collection[T]) Explain(condition filter.Condition) string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	p := c.plan(condition)
+	if p.candidates == nil {
+		return "full scan"
+	}
+	return fmt.Sprintf("%s -> %d candidates", p.description, len(p.candidates))
+}
+
+// plan is the result of the query planner. If candidates is nil, all items must be evaluated.
+type plan struct {
+	candidates  []int
+	description string
+}
+
+var fullScan = plan{}
+
+func (c *Collection[T]) plan(condition filter.Condition) plan {
+	switch cond := condition.(type) {
+	case nil:
+		return fullScan
+	case *filter.WhereCondition:
+		if cond.Condition == nil {
+			return fullScan
+		}
+		return c.plan(cond.Condition)
+	case *filter.GroupCondition:
+		return c.plan(cond.Condition)
+	case *filter.AndCondition:
+		var plans []plan
+		for _, sub := range cond.Conditions {
+			if p := c.plan(sub); p.candidates != nil {
+				plans = append(plans, p)
+			}
+		}
+		if len(plans) == 0 {
+			return fullScan
+		}
+		result := plans[0]
+		for _, p := range plans[1:] {
+			result = plan{
+				candidates:  intersectPositions(result.candidates, p.candidates),
+				description: result.description + " AND " + p.description,
+			}
+		}
+		return result
+	case *filter.OrCondition:
+		var result plan
+		for i, sub := range cond.Conditions {
+			p := c.plan(sub)
+			if p.candidates == nil {
+				return fullScan
+			}
+			if i == 0 {
+				result = p
+				continue
+			}
+			result = plan{
+				candidates:  unionPositions(result.candidates, p.candidates),
+				description: result.description + " OR " + p.description,
+			}
+		}
+		if len(cond.Conditions) > 1 {
+			result.description = "(" + result.description + ")"
+		}
+		return result
+	case *filter.EqualsCondition:
+		return c.lookup(c.hash, cond.Field, "hash", []any{cond.Value})
+	case *filter.InCondition:
+		values, ok := sliceValues(cond.Value)
+		if !ok {
+			return fullScan
+		}
+		return c.lookup(c.hash, cond.Field, "hash", values)
+	case *filter.ArrayContainsCondition:
+		return c.lookup(c.inverted, cond.Field, "inverted", []any{cond.Value})
+	case *filter.ArrayContainsArrayCondition:
+		return c.lookup(c.inverted, cond.Field, "inverted", []any{cond.Value})
+	case *filter.ArraysOverlapCondition:
+		values, ok := sliceValues(cond.Value)
+		if !ok {
+			return fullScan
+		}
+		return c.lookup(c.inverted, cond.Field, "inverted", values)
+	case *filter.OverlapsCondition:
+		values, ok := sliceValues(cond.Value)
+		if !ok {
+			return fullScan
+		}
+		return c.lookup(c.inverted, cond.Field, "inverted", values)
+	case *filter.GreaterThanCondition:
+		return c.rangeScan(cond.Field, cond.Value, func(c int) bool { return c > 0 }, true)
+	case *filter.GreaterThanOrEqualCondition:
+		return c.rangeScan(cond.Field, cond.Value, func(c int) bool { return c >= 0 }, true)
+	case *filter.LowerThanCondition:
+		return c.rangeScan(cond.Field, cond.Value, func(c int) bool { return c >= 0 }, false)
+	case *filter.LowerThanOrEqualCondition:
+		return c.rangeScan(cond.Field, cond.Value, func(c int) bool { return c > 0 }, false)
+	default:
+		return fullScan
+	}
+}
+
+func (c *Collection[T]) lookup(indexes map[string]*hashIndex, field, kind string, values []any) plan {
+	index, ok := indexes[field]
+	if !ok {
+		return fullScan
+	}
+	var candidates []int
+	for _, v := range values {
+		positions, ok := index.lookup(reflect.ValueOf(v))
+		if !ok {
+			return fullScan
+		}
+		candidates = unionPositions(candidates, positions)
+	}
+	if candidates == nil {
+		candidates = []int{}
+	}
+	return plan{
+		candidates:  candidates,
+		description: fmt.Sprintf("%s(%s)", kind, field),
+	}
+}
+
+// rangeScan uses the sorted index of the field. The entries from the first one matching the
+// boundary are candidates if upper is true, otherwise the entries before.
+func (c *Collection[T]) rangeScan(field string, value any, boundary func(c int) bool, upper bool) plan {
+	index, ok := c.sorted[field]
+	if !ok {
+		return fullScan
+	}
+	v := reflect.ValueOf(value)
+	if !indexableOperand(index.category, v) {
+		return fullScan
+	}
+	var compareErr error
+	i := sort.Search(len(index.entries), func(i int) bool {
+		c, err := compareValues(index.entries[i].value, v)
+		if err != nil {
+			compareErr = err
+		}
+		return boundary(c)
+	})
+	if compareErr != nil {
+		return fullScan
+	}
+	var entries []sortedEntry
+	if upper {
+		entries = index.entries[i:]
+	} else {
+		entries = index.entries[:i]
+	}
+	candidates := make([]int, 0, len(entries))
+	for _, entry := range entries {
+		candidates = append(candidates, entry.position)
+	}
+	sort.Ints(candidates)
+	return plan{
+		candidates:  candidates,
+		description: fmt.Sprintf("sorted(%s)", field),
+	}
+}
+
+// fieldType returns the type of the field of objects of type t.
+func fieldType(t reflect.Type, field string) (reflect.Type, error) {
+	var zero any
+	if t.Kind() == reflect.Ptr {
+		zero = reflect.New(t.Elem()).Interface()
+	} else {
+		zero = reflect.New(t).Elem().Interface()
+	}
+	segments, err := fieldPath(field)
+	if err != nil {
+		return nil, err
+	}
+	if hasWildcard(segments) {
+		return pathType(t, segments, field)
+	}
+	v, err := getField(zero, field)
+	if err != nil {
+		return nil, err
+	}
+	return v.Type(), nil
+}
+
+type indexCategory int
+
+const (
+	noCategory indexCategory = iota
+	numberCategory
+	stringCategory
+	timeCategory
+	boolCategory
+)
+
+// indexCategoryOf returns the category of values of the type. Only types whose values are
+// compared by their kind can be indexed.
+func indexCategoryOf(t reflect.Type) indexCategory {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t {
+	case timeType:
+		return timeCategory
+	case bigIntType, bigFloatType, bigRatType:
+		return numberCategory
+	}
+	if _, ok := lookupEnum(t); ok {
+		return noCategory
+	}
+	for _, name := range append([]string{"Equal"}, compareMethodNames...) {
+		if _, ok := t.MethodByName(name); ok {
+			return noCategory
+		}
+		if _, ok := reflect.PtrTo(t).MethodByName(name); ok {
+			return noCategory
+		}
+	}
+	if t.Implements(comparableType) || reflect.PtrTo(t).Implements(comparableType) {
+		return noCategory
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return numberCategory
+	case reflect.String:
+		return stringCategory
+	case reflect.Bool:
+		return boolCategory
+	default:
+		return noCategory
+	}
+}
+
+// indexableOperand reports whether an index of the category can be used for the operand.
+func indexableOperand(category indexCategory, v reflect.Value) bool {
+	_, ok := indexKey(category, v)
+	return ok
+}
+
+// indexKey returns the key of the value within an index of the given category. Values which are
+// equal according to valuesEqual have the same key. The second return value is false if the value
+// cannot be looked up in an index of the category.
+func indexKey(category indexCategory, v reflect.Value) (string, bool) {
+	v = indirect(v)
+	if !v.IsValid() {
+		return "", false
+	}
+	switch category {
+	case numberCategory:
+		if !isNumber(v) && v.Kind() != reflect.String {
+			return "", false
+		}
+		n, ok := toNumber(v)
+		if !ok {
+			return "", false
+		}
+		if n.inf != 0 {
+			return "inf:" + strconv.Itoa(n.inf), true
+		}
+		return n.rat.RatString(), true
+	case stringCategory:
+		if v.Kind() != reflect.String {
+			return "", false
+		}
+		return v.String(), true
+	case timeCategory:
+		if v.Type() != timeType {
+			return "", false
+		}
+		return v.Interface().(time.Time).UTC().Format(time.RFC3339Nano), true
+	case boolCategory:
+		if v.Kind() != reflect.Bool {
+			return "", false
+		}
+		return strconv.FormatBool(v.Bool()), true
+	default:
+		return "", false
+	}
+}
+
+type hashIndex struct {
+	category  indexCategory
+	positions map[string][]int
+}
+
+func newHashIndex(category indexCategory) *hashIndex {
+	return &hashIndex{
+		category:  category,
+		positions: make(map[string][]int),
+	}
+}
+
+func (i *hashIndex) add(v reflect.Value, position int) {
+	key, ok := indexKey(i.category, v)
+	if !ok {
+		return
+	}
+	positions := i.positions[key]
+	if len(positions) > 0 && positions[len(positions)-1] == position {
+		return
+	}
+	i.positions[key] = append(positions, position)
+}
+
+func (i *hashIndex) lookup(v reflect.Value) ([]int, bool) {
+	key, ok := indexKey(i.category, v)
+	if !ok {
+		return nil, false
+	}
+	return i.positions[key], true
+}
+
+type sortedEntry struct {
+	value    reflect.Value
+	position int
+}
+
+type sortedIndex struct {
+	category indexCategory
+	entries  []sortedEntry
+}
+
+// sortedValue returns the value to add to a sorted index. Nil values and NaN are not added,
+// since they are not ordered.
+func sortedValue(v reflect.Value) (reflect.Value, bool) {
+	v = indirect(v)
+	if !v.IsValid() {
+		return v, false
+	}
+	if (v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64) && math.IsNaN(v.Float()) {
+		return v, false
+	}
+	return v, true
+}
+
+// search returns the position to insert the value at to keep the entries sorted.
+func (i *sortedIndex) search(v reflect.Value) (int, error) {
+	var compareErr error
+	at := sort.Search(len(i.entries), func(j int) bool {
+		c, err := compareValues(i.entries[j].value, v)
+		if err != nil {
+			compareErr = err
+		}
+		return c > 0
+	})
+	return at, compareErr
+}
+
+// insert inserts the entry at the position returned by search.
+func (i *sortedIndex) insert(at int, v reflect.Value, position int) {
+	i.entries = append(i.entries, sortedEntry{})
+	copy(i.entries[at+1:], i.entries[at:])
+	i.entries[at] = sortedEntry{value: v, position: position}
+}
+
+// append appends the entry without keeping the entries sorted.
+func (i *sortedIndex) append(v reflect.Value, position int) {
+	i.entries = append(i.entries, sortedEntry{value: v, position: position})
+}
+
+// sort sorts appended entries by their value. Entries with equal values keep their order.
+func (i *sortedIndex) sort() error {
+	compare := i.comparison()
+	var compareErr error
+	sort.Slice(i.entries, func(a, b int) bool {
+		c, err := compare(i.entries[a].value, i.entries[b].value)
+		if err != nil && compareErr == nil {
+			compareErr = err
+		}
+		if c == 0 {
+			return i.entries[a].position < i.entries[b].position
+		}
+		return c < 0
+	})
+	return compareErr
+}
+
+// comparison returns the function to compare the values of the entries with. If all values
+// have the same type, which is compared by its kind, they are compared without compareValues.
+func (i *sortedIndex) comparison() func(a, b reflect.Value) (int, error) {
+	if len(i.entries) == 0 {
+		return compareValues
+	}
+	t := i.entries[0].value.Type()
+	for _, entry := range i.entries[1:] {
+		if entry.value.Type() != t {
+			return compareValues
+		}
+	}
+	if t == timeType {
+		return func(a, b reflect.Value) (int, error) {
+			x, y := a.Interface().(time.Time), b.Interface().(time.Time)
+			switch {
+			case x.Before(y):
+				return -1, nil
+			case x.After(y):
+				return 1, nil
+			default:
+				return 0, nil
+			}
+		}
+	}
+	if indexCategoryOf(t) == noCategory {
+		return compareValues
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(a, b reflect.Value) (int, error) {
+			return compareOrdered(a.Int(), b.Int()), nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(a, b reflect.Value) (int, error) {
+			return compareOrdered(a.Uint(), b.Uint()), nil
+		}
+	case reflect.Float32, reflect.Float64:
+		return func(a, b reflect.Value) (int, error) {
+			return compareOrdered(a.Float(), b.Float()), nil
+		}
+	case reflect.String:
+		return func(a, b reflect.Value) (int, error) {
+			return compareOrdered(a.String(), b.String()), nil
+		}
+	default:
+		return compareValues
+	}
+}
+
+func sliceValues(value any) ([]any, bool) {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, false
+	}
+	values := make([]any, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		values = append(values, v.Index(i).Interface())
+	}
+	return values, true
+}
+
+func intersectPositions(a, b []int) []int {
+	result := []int{}
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			result = append(result, a[i])
+			i++
+			j++
+		}
+	}
+	return result
+}
+
+func unionPositions(a, b []int) []int {
+	result := make([]int, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			result = append(result, a[i])
+			i++
+		case a[i] > b[j]:
+			result = append(result, b[j])
+			j++
+		default:
+			result = append(result, a[i])
+			i++
+			j++
+		}
+	}
+	result = append(result, a[i:]...)
+	return append(result, b[j:]...)
+}
@@ -0,0 +1,270 @@
+package filterobject
+
+import (
+	"github.com/stretchr/testify/require"
+	"github.com/xafelium/filter"
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func testCollectionObjects() []TestObject {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var objects []TestObject
+	for i := 0; i < 100; i++ {
+		taskType := "chore"
+		if i%3 == 0 {
+			taskType = "magic"
+		}
+		objects = append(objects, TestObject{
+			Id:        i,
+			TaskType:  taskType,
+			Name:      string(rune('a' + i%26)),
+			Nicknames: []string{string(rune('a' + i%5))},
+			HouseIds:  []int{i % 7, i % 11},
+			CreatedAt: start.Add(time.Duration(i) * time.Hour),
+		})
+	}
+	return objects
+}
+
+func TestCollectionQuery(t *testing.T) {
+	objects := testCollectionObjects()
+	collection, err := NewCollection(objects,
+		WithHashIndex("taskType"),
+		WithHashIndex("id"),
+		WithSortedIndex("createdAt"),
+		WithSortedIndex("id"),
+		WithInvertedIndex("houseIds"),
+		WithInvertedIndex("nicknames"),
+	)
+	require.NoError(t, err)
+	require.Equal(t, 100, collection.Len())
+
+	start := objects[0].CreatedAt
+	tests := []struct {
+		name      string
+		condition filter.Condition
+		plan      string
+	}{
+		{
+			name:      "no condition",
+			condition: filter.Where(nil),
+			plan:      "full scan",
+		},
+		{
+			name:      "equals",
+			condition: filter.Where(filter.Equals("taskType", "magic")),
+			plan:      "hash(taskType) -> 34 candidates",
+		},
+		{
+			name:      "equals with other numeric type",
+			condition: filter.Equals("id", int64(42)),
+			plan:      "hash(id) -> 1 candidates",
+		},
+		{
+			name:      "in",
+			condition: filter.In("id", []int{1, 2, 3, 500}),
+			plan:      "hash(id) -> 3 candidates",
+		},
+		{
+			name:      "range",
+			condition: filter.And(filter.GreaterThan("createdAt", start.Add(10*time.Hour)), filter.LowerThanOrEqual("createdAt", start.Add(20*time.Hour))),
+			plan:      "sorted(createdAt) AND sorted(createdAt) -> 10 candidates",
+		},
+		{
+			name:      "lower than",
+			condition: filter.LowerThan("id", 10),
+			plan:      "sorted(id) -> 10 candidates",
+		},
+		{
+			name:      "greater than or equal",
+			condition: filter.GreaterThanOrEqual("id", 90.5),
+			plan:      "sorted(id) -> 9 candidates",
+		},
+		{
+			name:      "array contains",
+			condition: filter.ArrayContains("houseIds", 3),
+			plan:      "inverted(houseIds) -> 21 candidates",
+		},
+		{
+			name:      "arrays overlap",
+			condition: filter.ArraysOverlap("nicknames", []string{"a", "b"}),
+			plan:      "inverted(nicknames) -> 40 candidates",
+		},
+		{
+			name: "partially indexed and",
+			condition: filter.Where(filter.And(
+				filter.Equals("taskType", "magic"),
+				filter.Regex("name", "^[a-f]$"),
+				filter.Group(filter.Or(filter.ArrayContains("houseIds", 2), filter.In("id", []int{3, 6}))),
+			)),
+			plan: "hash(taskType) AND (inverted(houseIds) OR hash(id)) -> 10 candidates",
+		},
+		{
+			name:      "partially indexed or",
+			condition: filter.Or(filter.Equals("taskType", "magic"), filter.Regex("name", "^a$")),
+			plan:      "full scan",
+		},
+		{
+			name:      "not indexable",
+			condition: filter.Not(filter.Equals("taskType", "magic")),
+			plan:      "full scan",
+		},
+		{
+			name:      "not indexable operand",
+			condition: filter.GreaterThan("createdAt", "2024-01-02"),
+			plan:      "full scan",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test := test
+
+			actual, err := collection.Query(test.condition)
+			require.NoError(t, err)
+
+			var expected []TestObject
+			for _, obj := range objects {
+				applies, err := FilterApplies(obj, test.condition)
+				require.NoError(t, err)
+				if applies {
+					expected = append(expected, obj)
+				}
+			}
+			require.Equal(t, len(expected), len(actual))
+			for i := range expected {
+				require.Equal(t, expected[i].Id, actual[i].Id)
+			}
+			require.Equal(t, test.plan, collection.Explain(test.condition))
+		})
+	}
+}
+
+func TestCollectionAddAndRemove(t *testing.T) {
+	collection, err := NewCollection([]*TestObject{
+		{Id: 1, TaskType: "a"},
+		{Id: 2, TaskType: "b"},
+		{Id: 3, TaskType: "a"},
+	}, WithHashIndex("taskType"), WithSortedIndex("id"))
+	require.NoError(t, err)
+
+	require.NoError(t, collection.Add(&TestObject{Id: 0, TaskType: "a"}))
+	result, err := collection.Query(filter.And(filter.Equals("taskType", "a"), filter.LowerThan("id", 3)))
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	require.Equal(t, 1, result[0].Id)
+	require.Equal(t, 0, result[1].Id)
+
+	removed, err := collection.Remove(filter.Equals("taskType", "a"))
+	require.NoError(t, err)
+	require.Equal(t, 3, removed)
+	require.Equal(t, 1, collection.Len())
+	require.Equal(t, "hash(taskType) -> 0 candidates", collection.Explain(filter.Equals("taskType", "a")))
+
+	result, err = collection.Query(filter.GreaterThan("id", 0))
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	require.Equal(t, 2, result[0].Id)
+}
+
+func TestCollectionSortedIndexRebuild(t *testing.T) {
+	objects := testCollectionObjects()
+	collection, err := NewCollection(objects, WithSortedIndex("createdAt"), WithSortedIndex("name"))
+	require.NoError(t, err)
+
+	removed, err := collection.Remove(filter.LowerThan("createdAt", objects[50].CreatedAt))
+	require.NoError(t, err)
+	require.Equal(t, 50, removed)
+	require.Equal(t, 50, collection.Len())
+
+	result, err := collection.Query(filter.GreaterThan("createdAt", objects[97].CreatedAt))
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	require.Equal(t, 98, result[0].Id)
+	require.Equal(t, 99, result[1].Id)
+
+	result, err = collection.Query(filter.LowerThan("name", "b"))
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	require.Equal(t, 52, result[0].Id)
+	require.Equal(t, 78, result[1].Id)
+	require.Equal(t, "sorted(name) -> 2 candidates", collection.Explain(filter.LowerThan("name", "b")))
+}
+
+func TestCollectionBigNumbers(t *testing.T) {
+	collection, err := NewCollection([]BigTestObject{
+		{Amount: big.NewRat(1, 2)},
+		{Amount: big.NewRat(3, 2)},
+		{},
+	}, WithHashIndex("amount"), WithSortedIndex("amount"))
+	require.NoError(t, err)
+
+	result, err := collection.Query(filter.Equals("amount", "1.5"))
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+
+	result, err = collection.Query(filter.GreaterThan("amount", 0.25))
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	require.Equal(t, "sorted(amount) -> 2 candidates", collection.Explain(filter.GreaterThan("amount", 0.25)))
+}
+
+func TestCollectionNaN(t *testing.T) {
+	collection, err := NewCollection([]BigTestObject{
+		{Weight: 1},
+		{Weight: math.NaN()},
+		{Weight: 2},
+	}, WithSortedIndex("weight"))
+	require.NoError(t, err)
+	require.NoError(t, collection.Add(BigTestObject{Weight: math.NaN()}))
+	require.NoError(t, collection.Add(BigTestObject{Weight: 3}))
+
+	result, err := collection.Query(filter.GreaterThan("weight", 1.5))
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	require.Equal(t, 2.0, result[0].Weight)
+	require.Equal(t, 3.0, result[1].Weight)
+	require.Equal(t, "sorted(weight) -> 2 candidates", collection.Explain(filter.GreaterThan("weight", 1.5)))
+}
+
+func TestCollectionAddIsAtomic(t *testing.T) {
+	collection, err := NewCollection([]TestObject{{Id: 1, TaskType: "a"}}, WithHashIndex("taskType"), WithSortedIndex("id"))
+	require.NoError(t, err)
+	// An entry which cannot be compared with ids lets the insertion into the sorted index fail.
+	index := collection.sorted["id"]
+	index.entries = append(index.entries, sortedEntry{value: reflect.ValueOf([]int{}), position: 0})
+
+	require.Error(t, collection.Add(TestObject{Id: 2, TaskType: "a"}))
+	require.Equal(t, 1, collection.Len())
+	require.Equal(t, map[string][]int{"a": {0}}, collection.hash["taskType"].positions)
+	require.Len(t, index.entries, 2)
+}
+
+func TestCollectionErrors(t *testing.T) {
+	_, err := NewCollection([]TestObject{}, WithHashIndex("unknownField"))
+	require.EqualError(t, err, "field 'unknownField' was not found on object")
+
+	_, err = NewCollection([]TestObject{}, WithInvertedIndex("name"))
+	require.EqualError(t, err, "field 'name' must be of type slice/array for an inverted index but is of type string")
+
+	_, err = NewCollection([]TestObject{}, WithHashIndex("childObject"))
+	require.EqualError(t, err, "field 'childObject' of type *filterobject.TestObject cannot be used for a hash index")
+
+	_, err = NewCollection([]Order{{Items: []OrderItem{{Sku: "a"}}}}, WithHashIndex("items[*].sku"))
+	require.EqualError(t, err, "field 'items[*].sku' has multiple values and cannot be used for a hash index")
+
+	orders, err := NewCollection([]Order{{Items: []OrderItem{{Sku: "a"}}}}, WithHashIndex("items[0].sku"))
+	require.NoError(t, err)
+	require.Equal(t, "hash(items[0].sku) -> 1 candidates", orders.Explain(filter.Equals("items[0].sku", "a")))
+
+	_, err = NewCollection([]EnumTestObject{}, WithSortedIndex("priority"))
+	require.EqualError(t, err, "field 'priority' of type filterobject.Priority cannot be used for a sorted index")
+
+	collection, err := NewCollection([]TestObject{{}})
+	require.NoError(t, err)
+	_, err = collection.Query(filter.And(filter.Equals("id", 0)))
+	require.Error(t, err)
+}
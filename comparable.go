@@ -0,0 +1,187 @@
+package filterobject
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Comparable is implemented by types which define their own order.
+// Compare returns -1, 0 or +1 depending on whether the receiver is lower than,
+// equal to or greater than other. An error must be returned if other cannot be
+// compared with the receiver.
+//
+// Besides Comparable, methods with the signatures Compare(T) int, Cmp(T) int and
+// Equal(T) bool are detected and used when the other value is assignable to T.
+type Comparable interface {
+	Compare(other any) (int, error)
+}
+
+var comparableType = reflect.TypeOf((*Comparable)(nil)).Elem()
+
+var compareMethodNames = []string{"Compare", "Cmp"}
+
+// comparisonMethods describes which methods values of a type or pointers to them have to
+// compare them with other values.
+type comparisonMethods struct {
+	comparable bool
+	compare    bool
+	equal      bool
+}
+
+// comparisonMethodsCache caches the comparisonMethods by reflect.Type.
+var comparisonMethodsCache sync.Map
+
+// predeclaredTypes contains the predeclared types like int or string by their kind.
+var predeclaredTypes = func() (types [reflect.UnsafePointer + 1]reflect.Type) {
+	for _, v := range []any{
+		false, 0, int8(0), int16(0), int32(0), int64(0), uint(0), uint8(0), uint16(0), uint32(0),
+		uint64(0), uintptr(0), float32(0), float64(0), complex64(0), complex128(0), "",
+	} {
+		t := reflect.TypeOf(v)
+		types[t.Kind()] = t
+	}
+	return types
+}()
+
+// isPredeclared reports whether t is a predeclared type like int or string. Neither these
+// types nor pointers to them have methods.
+func isPredeclared(t reflect.Type) bool {
+	return predeclaredTypes[t.Kind()] == t
+}
+
+// comparisonMethodsOf returns the comparison methods of values of the type.
+func comparisonMethodsOf(t reflect.Type) comparisonMethods {
+	if isPredeclared(t) {
+		return comparisonMethods{}
+	}
+	if cached, ok := comparisonMethodsCache.Load(t); ok {
+		return cached.(comparisonMethods)
+	}
+	var m comparisonMethods
+	p := reflect.PtrTo(t)
+	if p.NumMethod() > 0 {
+		m.comparable = p.Implements(comparableType)
+		for _, name := range compareMethodNames {
+			if _, ok := p.MethodByName(name); ok {
+				m.compare = true
+			}
+		}
+		_, m.equal = p.MethodByName("Equal")
+	}
+	comparisonMethodsCache.Store(t, m)
+	return m
+}
+
+// compareComparable compares values implementing Comparable or having a Compare or Cmp method.
+// The second return value is false if neither value provides an order.
+func compareComparable(field, value reflect.Value) (int, bool, error) {
+	fm, vm := comparisonMethodsOf(field.Type()), comparisonMethodsOf(value.Type())
+	if !fm.comparable && !fm.compare && !vm.comparable && !vm.compare {
+		return 0, false, nil
+	}
+	if !field.CanInterface() || !value.CanInterface() {
+		return 0, false, nil
+	}
+	if fm.comparable {
+		if c, ok := asComparable(field); ok {
+			result, err := c.Compare(value.Interface())
+			return result, true, err
+		}
+	}
+	if vm.comparable {
+		if c, ok := asComparable(value); ok {
+			result, err := c.Compare(field.Interface())
+			return -result, true, err
+		}
+	}
+	for _, name := range compareMethodNames {
+		if !fm.compare && !vm.compare {
+			break
+		}
+		if result, ok := callMethod(field, name, value, reflect.Int); fm.compare && ok {
+			return int(result.Int()), true, nil
+		}
+		if result, ok := callMethod(value, name, field, reflect.Int); vm.compare && ok {
+			return -int(result.Int()), true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// equalMethod compares values having an Equal method.
+// The second return value is false if neither value has a matching Equal method.
+func equalMethod(field, value reflect.Value) (bool, bool) {
+	if comparisonMethodsOf(field.Type()).equal {
+		if result, ok := callMethod(field, "Equal", value, reflect.Bool); ok {
+			return result.Bool(), true
+		}
+	}
+	if comparisonMethodsOf(value.Type()).equal {
+		if result, ok := callMethod(value, "Equal", field, reflect.Bool); ok {
+			return result.Bool(), true
+		}
+	}
+	return false, false
+}
+
+func asComparable(v reflect.Value) (Comparable, bool) {
+	for _, candidate := range methodReceivers(v) {
+		if candidate.Type().Implements(comparableType) {
+			return candidate.Interface().(Comparable), true
+		}
+	}
+	return nil, false
+}
+
+// callMethod calls the method with the given name on v if it accepts arg and returns
+// a single value of the given kind.
+func callMethod(v reflect.Value, name string, arg reflect.Value, result reflect.Kind) (reflect.Value, bool) {
+	if !arg.CanInterface() {
+		return reflect.Value{}, false
+	}
+	for _, receiver := range methodReceivers(v) {
+		method := receiver.MethodByName(name)
+		if !method.IsValid() {
+			continue
+		}
+		methodType := method.Type()
+		if methodType.NumIn() != 1 || methodType.NumOut() != 1 || methodType.Out(0).Kind() != result {
+			continue
+		}
+		in, ok := methodArgument(arg, methodType.In(0))
+		if !ok {
+			continue
+		}
+		return method.Call([]reflect.Value{in})[0], true
+	}
+	return reflect.Value{}, false
+}
+
+// methodReceivers returns v and, if possible, a pointer to v, so methods with value and
+// pointer receivers are found.
+func methodReceivers(v reflect.Value) []reflect.Value {
+	if !v.IsValid() || !v.CanInterface() {
+		return nil
+	}
+	receivers := []reflect.Value{v}
+	if v.CanAddr() {
+		receivers = append(receivers, v.Addr())
+	}
+	return receivers
+}
+
+// methodArgument converts arg into a value assignable to the parameter type t.
+func methodArgument(arg reflect.Value, t reflect.Type) (reflect.Value, bool) {
+	if arg.Type().AssignableTo(t) {
+		return arg, true
+	}
+	if arg.CanAddr() && arg.Addr().Type().AssignableTo(t) {
+		return arg.Addr(), true
+	}
+	if t.Kind() == reflect.Ptr && arg.Type().AssignableTo(t.Elem()) {
+		ptr := reflect.New(t.Elem())
+		ptr.Elem().Set(arg)
+		return ptr, true
+	}
+	return reflect.Value{}, false
+}
@@ -0,0 +1,128 @@
+package filterobject
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"github.com/xafelium/filter"
+	"math/big"
+	"testing"
+)
+
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+func (v Version) Compare(other any) (int, error) {
+	o, ok := other.(Version)
+	if !ok {
+		s, isString := other.(string)
+		if !isString {
+			return 0, fmt.Errorf("cannot compare version with %T", other)
+		}
+		if _, err := fmt.Sscanf(s, "%d.%d.%d", &o.Major, &o.Minor, &o.Patch); err != nil {
+			return 0, err
+		}
+	}
+	for _, d := range []int{v.Major - o.Major, v.Minor - o.Minor, v.Patch - o.Patch} {
+		if d < 0 {
+			return -1, nil
+		}
+		if d > 0 {
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+type Money struct {
+	Cents int64
+}
+
+func (m Money) Cmp(other Money) int {
+	return compareOrdered(m.Cents, other.Cents)
+}
+
+type Label struct {
+	Name  string
+	Color string
+}
+
+func (l *Label) Equal(other *Label) bool {
+	return l.Name == other.Name
+}
+
+type ComparableTestObject struct {
+	Version Version
+	Price   Money
+	Counter *big.Int
+	Label   Label
+}
+
+func TestComparable(t *testing.T) {
+	var applies bool
+	var err error
+	obj := ComparableTestObject{
+		Version: Version{Major: 1, Minor: 4, Patch: 2},
+		Price:   Money{Cents: 1999},
+		Counter: big.NewInt(42),
+		Label:   Label{Name: "urgent", Color: "red"},
+	}
+
+	// Comparable interface
+	applies, err = applyGreaterThan(obj, filter.GreaterThan("version", Version{Major: 1, Minor: 3}))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyLowerThanOrEqual(obj, filter.LowerThanOrEqual("version", "1.4.2"))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyEquals(obj, filter.Equals("version", "1.4.2"))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyIn(obj, filter.In("version", []Version{{Major: 2}, {Major: 1, Minor: 4, Patch: 2}}))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyGreaterThan(obj, filter.GreaterThan("version", 1))
+	require.Error(t, err)
+	require.False(t, applies)
+
+	// Comparable operand
+	applies, err = applyLowerThan(ComparableTestObject{}, filter.LowerThan("version", Version{Major: 1}))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	// Cmp method
+	applies, err = applyGreaterThan(obj, filter.GreaterThan("price", Money{Cents: 1000}))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyLowerThan(obj, filter.LowerThan("price", Money{Cents: 1000}))
+	require.NoError(t, err)
+	require.False(t, applies)
+
+	applies, err = applyEquals(obj, filter.Equals("price", &Money{Cents: 1999}))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyGreaterThan(obj, filter.GreaterThan("counter", big.NewInt(41)))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyIn(obj, filter.In("counter", []*big.Int{big.NewInt(1), big.NewInt(42)}))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	// Equal method with pointer receiver
+	applies, err = applyEquals(&obj, filter.Equals("label", Label{Name: "urgent"}))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyEquals(&obj, filter.Equals("label", Label{Name: "later", Color: "red"}))
+	require.NoError(t, err)
+	require.False(t, applies)
+}
@@ -0,0 +1,183 @@
+package filterobject
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// errNilValue is returned by compareValues if one of the values is nil.
+var errNilValue = errors.New("cannot compare nil values")
+
+// indirect dereferences pointers and interfaces until a concrete value is reached.
+// It returns an invalid value if a nil pointer or interface is encountered.
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// compareValues compares the field with the value and returns -1, 0 or +1
+// depending on whether the field is lower than, equal to or greater than the value.
+func compareValues(field, value reflect.Value) (int, error) {
+	f, v := indirect(field), indirect(value)
+	if !f.IsValid() || !v.IsValid() {
+		return 0, errNilValue
+	}
+	if c, ok, err := compareTime(f, v); ok || err != nil {
+		return c, err
+	}
+	if c, ok, err := compareEnum(f, v); ok || err != nil {
+		return c, err
+	}
+	if c, ok, err := compareComparable(f, v); ok || err != nil {
+		return c, err
+	}
+	if f.CanInt() && v.CanInt() {
+		return compareOrdered(f.Int(), v.Int()), nil
+	}
+	if f.CanUint() && v.CanUint() {
+		return compareOrdered(f.Uint(), v.Uint()), nil
+	}
+	if f.CanFloat() && v.CanFloat() {
+		return compareOrdered(f.Float(), v.Float()), nil
+	}
+	if c, ok, err := compareNumbers(f, v); ok || err != nil {
+		return c, err
+	}
+	if f.Kind() == reflect.String && v.Kind() == reflect.String {
+		return compareOrdered(f.String(), v.String()), nil
+	}
+	return 0, fmt.Errorf("cannot compare variables of type %s and %s", f.Kind(), v.Kind())
+}
+
+// valuesEqual reports whether the field is equal to the value. Values which cannot be
+// compared with each other are not equal.
+func valuesEqual(field, value reflect.Value) (bool, error) {
+	f, v := indirect(field), indirect(value)
+	if !f.IsValid() || !v.IsValid() {
+		return !f.IsValid() && !v.IsValid(), nil
+	}
+	if equal, ok := equalMethod(f, v); ok {
+		return equal, nil
+	}
+	c, err := compareValues(f, v)
+	if err == nil {
+		return c == 0, nil
+	}
+	if f.Kind() != reflect.String && v.Kind() == reflect.String {
+		s, err := stringValue(f)
+		return err == nil && s == v.String(), nil
+	}
+	v = convertToType(v, f.Type())
+	if f.Type() != v.Type() || !f.Type().Comparable() || !f.CanInterface() || !v.CanInterface() {
+		return false, nil
+	}
+	return f.Interface() == v.Interface(), nil
+}
+
+type ordered interface {
+	~int64 | ~uint64 | ~float64 | ~string
+}
+
+func compareOrdered[T ordered](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// typesComparable reports whether values of the types may be equal according to valuesEqual.
+// Interface types may hold values of any type and are comparable with all types.
+func typesComparable(a, b reflect.Type) bool {
+	a, b = derefType(a), derefType(b)
+	switch {
+	case a.Kind() == reflect.Interface || b.Kind() == reflect.Interface:
+		return true
+	case a == b || (a.Kind() == b.Kind() && a.ConvertibleTo(b)):
+		return true
+	case isNumberType(a) && isNumberType(b):
+		return true
+	case hasOwnComparison(a) || hasOwnComparison(b):
+		return true
+	}
+	return stringComparable(a, b) || stringComparable(b, a)
+}
+
+// stringComparable reports whether values of type a may be compared with values of the
+// string type b, e.g. times with RFC 3339 strings or enums with the names of their members.
+func stringComparable(a, b reflect.Type) bool {
+	if b.Kind() != reflect.String {
+		return false
+	}
+	return a.Kind() == reflect.String || a == timeType || a == durationType || isBigNumberType(a) || hasStringRepresentation(a)
+}
+
+// hasOwnComparison reports whether values of the type are compared by their methods.
+func hasOwnComparison(t reflect.Type) bool {
+	m := comparisonMethodsOf(t)
+	return m.comparable || m.compare || m.equal
+}
+
+func isNumberType(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return isBigNumberType(t)
+}
+
+func isBigNumberType(t reflect.Type) bool {
+	return t == bigIntType || t == bigFloatType || t == bigRatType
+}
+
+// valueSet finds values equal to a value according to valuesEqual.
+type valueSet struct {
+	values reflect.Value
+	hashed map[any]struct{}
+}
+
+// newValueSet creates a set of the elements of the slice or array values. If the elements are
+// of type t and are compared by their kind only, they are looked up by hash.
+func newValueSet(values reflect.Value, t reflect.Type) *valueSet {
+	s := &valueSet{values: values}
+	elemType := values.Type().Elem()
+	if elemType != t || hasOwnComparison(t) {
+		return s
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		s.hashed = make(map[any]struct{}, values.Len())
+		for i := 0; i < values.Len(); i++ {
+			s.hashed[values.Index(i).Interface()] = struct{}{}
+		}
+	}
+	return s
+}
+
+// contains reports whether the set contains a value equal to the field.
+func (s *valueSet) contains(field reflect.Value) (bool, error) {
+	if s.hashed != nil {
+		_, found := s.hashed[field.Interface()]
+		return found, nil
+	}
+	for i := 0; i < s.values.Len(); i++ {
+		equal, err := valuesEqual(field, s.values.Index(i))
+		if err != nil || equal {
+			return equal, err
+		}
+	}
+	return false, nil
+}
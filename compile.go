@@ -0,0 +1,455 @@
+package filterobject
+
+import (
+	"context"
+	"fmt"
+	"github.com/xafelium/filter"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// defaultConditionCosts contains the estimated relative costs to evaluate leaf conditions.
+var defaultConditionCosts = map[string]float64{
+	filter.EqualsConditionType:             1,
+	filter.NotEqualsConditionType:          1,
+	filter.IsNilConditionType:              1,
+	filter.NotNilConditionType:             1,
+	filter.GreaterThanConditionType:        2,
+	filter.GreaterThanOrEqualConditionType: 3,
+	filter.LowerThanConditionType:          2,
+	filter.LowerThanOrEqualConditionType:   3,
+	filter.InConditionType:                 2,
+	filter.ContainsConditionType:           5,
+	filter.ArrayContainsConditionType:      4,
+	filter.ArrayContainsArrayConditionType: 4,
+	filter.ArrayIsContainedConditionType:   8,
+	filter.ArraysOverlapConditionType:      8,
+	filter.OverlapsConditionType:           8,
+	filter.RegexConditionType:              20,
+	filter.NotRegexConditionType:           20,
+	LengthEqualsConditionType:              1,
+	LengthGreaterThanConditionType:         1,
+	LengthLowerThanConditionType:           1,
+	IsEmptyConditionType:                   1,
+	NotEmptyConditionType:                  1,
+	HasKeyConditionType:                    1,
+	HasAnyKeyConditionType:                 2,
+	HasAllKeysConditionType:                2,
+	StartsWithConditionType:                3,
+	EndsWithConditionType:                  3,
+	ContainsTextConditionType:              5,
+}
+
+// defaultConditionCost is used for conditions without an estimated cost.
+const defaultConditionCost = 5
+
+type compileOptions struct {
+	costs           map[string]float64
+	reorderInterval int64
+	observer        Observer
+	memoize         bool
+	coerce          bool
+	multiValueMode  MultiValueMode
+	clock           Clock
+}
+
+// CompileOption configures the compilation of a condition.
+type CompileOption func(o *compileOptions)
+
+// WithConditionCost sets the estimated relative cost to evaluate conditions of the given type.
+func WithConditionCost(conditionType string, cost float64) CompileOption {
+	return func(o *compileOptions) {
+		o.costs[conditionType] = cost
+	}
+}
+
+// WithAdaptiveOrdering reorders the sub-conditions of And and Or conditions every interval
+// evaluations by their estimated cost and their observed selectivity, so sub-conditions which
+// are cheap and often decide the result are evaluated first. Older observations lose weight
+// with every reordering.
+func WithAdaptiveOrdering(interval int) CompileOption {
+	return func(o *compileOptions) {
+		o.reorderInterval = int64(interval)
+	}
+}
+
+// WithObserver notifies the observer about the evaluation of every condition of the compiled condition.
+func WithObserver(observer Observer) CompileOption {
+	return func(o *compileOptions) {
+		o.observer = observer
+	}
+}
+
+// WithCoercion converts the operands of conditions into the type of the field of the evaluated
+// object before they are compared, e.g. the float64 operands of decoded conditions into the int
+// type of a field or the []any operands into the []string type of a field. Operands are converted
+// as CoerceCondition does, but with the actual type of the field, so it also applies to fields of
+// type any. An operand which cannot be converted without losing information is an evaluation error.
+func WithCoercion() CompileOption {
+	return func(o *compileOptions) {
+		o.coerce = true
+	}
+}
+
+// CompiledCondition is a condition prepared for repeated evaluation.
+// The sub-conditions of And and Or conditions are ordered by their estimated cost, so cheap
+// sub-conditions can short-circuit the evaluation of expensive ones. Since sub-conditions
+// are reordered, errors of sub-conditions may not be reported if another sub-condition
+// already decides the result.
+//
+// A CompiledCondition is safe for concurrent use.
+type CompiledCondition struct {
+	condition filter.Condition
+	root      node
+}
+
+// Compile compiles the condition.
+func Compile(condition filter.Condition, opts ...CompileOption) (*CompiledCondition, error) {
+	options := &compileOptions{
+		costs: make(map[string]float64, len(defaultConditionCosts)),
+	}
+	for t, cost := range defaultConditionCosts {
+		options.costs[t] = cost
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	root, err := compileNode(condition, options)
+	if err != nil {
+		return nil, err
+	}
+	if options.observer != nil {
+		root = observeRoot(condition, root, options.observer)
+	}
+	return &CompiledCondition{
+		condition: condition,
+		root:      root,
+	}, nil
+}
+
+// Condition returns the condition which was compiled.
+func (c *CompiledCondition) Condition() filter.Condition {
+	return c.condition
+}
+
+// Applies reports whether the condition applies to the object.
+func (c *CompiledCondition) Applies(obj any) (bool, error) {
+	return c.AppliesContext(context.Background(), obj)
+}
+
+// AppliesContext reports whether the condition applies to the object.
+// The context is passed to the Observer of the compiled condition.
+func (c *CompiledCondition) AppliesContext(ctx context.Context, obj any) (bool, error) {
+	if c.root == nil {
+		return true, nil
+	}
+	return c.root.applies(ctx, obj)
+}
+
+// String returns the string representation of the compiled condition.
+func (c *CompiledCondition) String() string {
+	if c.root == nil {
+		return ""
+	}
+	return c.root.String()
+}
+
+type node interface {
+	applies(ctx context.Context, obj any) (bool, error)
+	cost() float64
+	String() string
+}
+
+func compileNode(condition filter.Condition, options *compileOptions) (node, error) {
+	n, err := compileUnobservedNode(condition, options)
+	if err != nil || n == nil {
+		return n, err
+	}
+	if options.observer != nil {
+		n = &observedNode{
+			condition: condition,
+			child:     n,
+			observer:  options.observer,
+		}
+	}
+	if options.memoize {
+		n = &memoNode{
+			key:   conditionKey(condition),
+			child: n,
+		}
+	}
+	return n, nil
+}
+
+func compileUnobservedNode(condition filter.Condition, options *compileOptions) (node, error) {
+	if condition == nil || (reflect.ValueOf(condition).Kind() == reflect.Ptr && reflect.ValueOf(condition).IsNil()) {
+		return nil, nil
+	}
+	switch c := condition.(type) {
+	case *filter.WhereCondition:
+		return compileNode(c.Condition, options)
+	case *filter.GroupCondition:
+		return compileNode(c.Condition, options)
+	case *filter.NotCondition:
+		child, err := compileChild(c.Condition, options)
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{child: child}, nil
+	case *filter.AndCondition:
+		if len(c.Conditions) < 2 {
+			return nil, fmt.Errorf("AND condition must have at least two conditions")
+		}
+		return compileJunction(c.Conditions, false, options)
+	case *filter.OrCondition:
+		if len(c.Conditions) < 2 {
+			return nil, fmt.Errorf("OR condition must have at least two conditions")
+		}
+		return compileJunction(c.Conditions, true, options)
+	}
+	evaluate, ok := conditionEvaluators[condition.Type()]
+	if !ok {
+		return nil, fmt.Errorf("unknown condition: %s", condition.Type())
+	}
+	cost, ok := options.costs[condition.Type()]
+	if !ok {
+		cost = defaultConditionCost
+	}
+	if in, ok := condition.(*filter.InCondition); ok {
+		if v := reflect.ValueOf(in.Value); v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+			cost += float64(v.Len()) / 10
+		}
+	}
+	n := &leafNode{
+		condition:     condition,
+		evaluate:      evaluate,
+		estimatedCost: cost,
+	}
+	if options.coerce {
+		n.coercion, _ = leafCoercionOf(condition)
+	}
+	n.multiValuedField, n.multiValued = multiValuedField(condition)
+	n.multiValueMode = options.multiValueMode
+	if leaf, ok := leafCoercionOf(condition); ok && hasRelativeTime(reflect.ValueOf(leaf.value)) {
+		n.relativeTime = true
+		n.clock = options.clock
+	}
+	return n, nil
+}
+
+// compileChild compiles the sub-condition of a Not, And or Or condition. Like with
+// FilterApplies, nil sub-conditions apply to every object.
+func compileChild(condition filter.Condition, options *compileOptions) (node, error) {
+	child, err := compileNode(condition, options)
+	if err != nil || child != nil {
+		return child, err
+	}
+	return emptyNode{}, nil
+}
+
+func compileJunction(conditions []filter.Condition, or bool, options *compileOptions) (node, error) {
+	n := &junctionNode{
+		or:              or,
+		reorderInterval: options.reorderInterval,
+	}
+	for _, c := range conditions {
+		child, err := compileChild(c, options)
+		if err != nil {
+			return nil, err
+		}
+		n.branches = append(n.branches, &branch{node: child})
+	}
+	order := append([]*branch(nil), n.branches...)
+	sort.SliceStable(order, func(i, j int) bool {
+		return order[i].node.cost() < order[j].node.cost()
+	})
+	n.order.Store(order)
+	return n, nil
+}
+
+type leafNode struct {
+	condition     filter.Condition
+	evaluate      ConditionEvaluator
+	estimatedCost float64
+	coercion      *leafCoercion
+	// multiValued is set for conditions on fields with multiple values.
+	multiValued      bool
+	multiValuedField string
+	multiValueMode   MultiValueMode
+	// relativeTime is set for conditions with relative time values, which are resolved with
+	// the clock or, if it is nil, with the clock set with SetClock.
+	relativeTime bool
+	clock        Clock
+	// coerced caches the coerced conditions by the type of the field.
+	coerced sync.Map
+}
+
+type coercedCondition struct {
+	condition filter.Condition
+	err       error
+}
+
+func (n *leafNode) applies(_ context.Context, obj any) (bool, error) {
+	if n.multiValued {
+		return applyMultiValued(obj, n.multiValuedField, n.multiValueMode, n.appliesTo)
+	}
+	return n.appliesTo(obj)
+}
+
+func (n *leafNode) appliesTo(obj any) (bool, error) {
+	condition, err := n.coerce(obj)
+	if err != nil {
+		return false, err
+	}
+	if n.relativeTime {
+		clock := n.clock
+		if clock == nil {
+			clock = currentClock()
+		}
+		if condition, err = resolveRelativeTimes(obj, condition, clock); err != nil {
+			return false, err
+		}
+	}
+	return n.evaluate(obj, condition)
+}
+
+// coerce returns the condition with its operand converted into the type of the field of the object.
+func (n *leafNode) coerce(obj any) (filter.Condition, error) {
+	if n.coercion == nil {
+		return n.condition, nil
+	}
+	field, err := getField(obj, n.coercion.field)
+	if err != nil {
+		return nil, err
+	}
+	field = indirect(field)
+	if !field.IsValid() {
+		return n.condition, nil
+	}
+	cached, ok := n.coerced.Load(field.Type())
+	if !ok {
+		condition, err := n.coercion.coerce(field.Type())
+		cached, _ = n.coerced.LoadOrStore(field.Type(), coercedCondition{condition: condition, err: err})
+	}
+	coerced := cached.(coercedCondition)
+	return coerced.condition, coerced.err
+}
+
+func (n *leafNode) cost() float64 {
+	return n.estimatedCost
+}
+
+func (n *leafNode) String() string {
+	return n.condition.String()
+}
+
+type notNode struct {
+	child node
+}
+
+func (n *notNode) applies(ctx context.Context, obj any) (bool, error) {
+	applies, err := n.child.applies(ctx, obj)
+	return !applies, err
+}
+
+func (n *notNode) cost() float64 {
+	return n.child.cost()
+}
+
+func (n *notNode) String() string {
+	return fmt.Sprintf("not ( %s )", n.child.String())
+}
+
+// branch is a sub-condition of an And or Or condition with its observed selectivity.
+type branch struct {
+	node        node
+	evaluations int64
+	decisive    int64
+}
+
+// rank returns the expected cost per decisive evaluation. Branches with a lower rank are evaluated first.
+func (b *branch) rank() float64 {
+	evaluations := atomic.LoadInt64(&b.evaluations)
+	decisive := atomic.LoadInt64(&b.decisive)
+	probability := (float64(decisive) + 1) / (float64(evaluations) + 2)
+	return b.node.cost() / probability
+}
+
+// junctionNode is an And or Or condition. The evaluation of an And condition stops at the first
+// sub-condition which does not apply, the evaluation of an Or condition at the first one which applies.
+type junctionNode struct {
+	or              bool
+	branches        []*branch
+	order           atomic.Value
+	reorderInterval int64
+	evaluations     int64
+	reorderMutex    sync.Mutex
+}
+
+func (n *junctionNode) applies(ctx context.Context, obj any) (bool, error) {
+	adaptive := n.reorderInterval > 0
+	if adaptive && atomic.AddInt64(&n.evaluations, 1)%n.reorderInterval == 0 {
+		n.reorder()
+	}
+	for _, b := range n.order.Load().([]*branch) {
+		applies, err := b.node.applies(ctx, obj)
+		if err != nil {
+			return false, err
+		}
+		if adaptive {
+			atomic.AddInt64(&b.evaluations, 1)
+		}
+		if applies == n.or {
+			if adaptive {
+				atomic.AddInt64(&b.decisive, 1)
+			}
+			return applies, nil
+		}
+	}
+	return !n.or, nil
+}
+
+// reorder orders the branches by their rank and halves the observations, so recent
+// evaluations have a higher weight.
+func (n *junctionNode) reorder() {
+	if !n.reorderMutex.TryLock() {
+		return
+	}
+	defer n.reorderMutex.Unlock()
+	order := append([]*branch(nil), n.branches...)
+	ranks := make(map[*branch]float64, len(order))
+	for _, b := range order {
+		ranks[b] = b.rank()
+		atomic.StoreInt64(&b.evaluations, atomic.LoadInt64(&b.evaluations)/2)
+		atomic.StoreInt64(&b.decisive, atomic.LoadInt64(&b.decisive)/2)
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return ranks[order[i]] < ranks[order[j]]
+	})
+	n.order.Store(order)
+}
+
+func (n *junctionNode) cost() float64 {
+	var cost float64
+	for _, b := range n.branches {
+		cost += b.node.cost()
+	}
+	return cost
+}
+
+func (n *junctionNode) String() string {
+	operator := " and "
+	if n.or {
+		operator = " or "
+	}
+	var str string
+	for _, b := range n.order.Load().([]*branch) {
+		if str != "" {
+			str += operator
+		}
+		str += "(" + b.node.String() + ")"
+	}
+	return str
+}
@@ -0,0 +1,164 @@
+package filterobject
+
+import (
+	"github.com/stretchr/testify/require"
+	"github.com/xafelium/filter"
+	"testing"
+)
+
+type unknownCondition struct{}
+
+func (c *unknownCondition) String() string {
+	return "unknown"
+}
+
+func (c *unknownCondition) Type() string {
+	return "UnknownCondition"
+}
+
+func TestCompile(t *testing.T) {
+	objects := []TestObject{
+		{Id: 42, Name: "Harry Potter", TaskType: "magic", HouseIds: []int{1, 2}},
+		{Id: 7, Name: "Hermine Granger", TaskType: "magic", Nicknames: []string{"Mine"}},
+		{Id: 9, Name: "Ron", TaskType: "chess", ChildObject: &TestObject{}},
+	}
+	conditions := []filter.Condition{
+		nil,
+		filter.Where(nil),
+		filter.Group(nil),
+		filter.Not(nil),
+		filter.And(nil, filter.Equals("name", "Ron")),
+		filter.Or(filter.Not(filter.Group(nil)), filter.Equals("name", "Ron")),
+		filter.Equals("name", "Ron"),
+		filter.Where(filter.And(
+			filter.Regex("name", "^H"),
+			filter.Equals("taskType", "magic"),
+			filter.Group(filter.Or(filter.ArrayContains("houseIds", 2), filter.Equals("id", 7))),
+		)),
+		filter.Where(filter.Or(
+			filter.Not(filter.IsNil("childObject")),
+			filter.In("id", []int{7, 8}),
+			filter.ArraysOverlap("nicknames", []string{"Harry"}),
+		)),
+	}
+	for _, condition := range conditions {
+		compiled, err := Compile(condition)
+		require.NoError(t, err)
+		require.Equal(t, condition, compiled.Condition())
+		for _, obj := range objects {
+			expected, err := FilterApplies(obj, condition)
+			require.NoError(t, err)
+			actual, err := compiled.Applies(obj)
+			require.NoError(t, err)
+			require.Equal(t, expected, actual, "%v for %+v", condition, obj)
+		}
+	}
+}
+
+func TestCompileOrdersByCost(t *testing.T) {
+	condition := filter.And(
+		filter.Regex("name", "^H"),
+		filter.Contains("taskType", "ag"),
+		filter.Or(filter.In("id", []int{1, 2, 3}), filter.Equals("id", 7)),
+	)
+
+	compiled, err := Compile(condition)
+	require.NoError(t, err)
+	require.Equal(t, "((id = 7) or (id IN ([1 2 3]))) and (taskType contains ag) and (name matchesRegex(^H))", compiled.String())
+
+	compiled, err = Compile(condition, WithConditionCost(filter.RegexConditionType, 0.5))
+	require.NoError(t, err)
+	require.Equal(t, "(name matchesRegex(^H)) and ((id = 7) or (id IN ([1 2 3]))) and (taskType contains ag)", compiled.String())
+
+	// The cheap condition decides the result before the invalid regular expression is evaluated
+	invalid := filter.And(filter.Regex("name", "("), filter.Equals("id", 1))
+	_, err = FilterApplies(TestObject{}, invalid)
+	require.Error(t, err)
+	compiled, err = Compile(invalid)
+	require.NoError(t, err)
+	applies, err := compiled.Applies(TestObject{})
+	require.NoError(t, err)
+	require.False(t, applies)
+}
+
+func TestCompileAdaptiveOrdering(t *testing.T) {
+	condition := filter.Or(
+		filter.Equals("name", "Harry"),
+		filter.Equals("taskType", "magic"),
+	)
+	compiled, err := Compile(condition, WithAdaptiveOrdering(10))
+	require.NoError(t, err)
+	require.Equal(t, "(name = Harry) or (taskType = magic)", compiled.String())
+
+	for i := 0; i < 20; i++ {
+		applies, err := compiled.Applies(TestObject{Name: "Ron", TaskType: "magic"})
+		require.NoError(t, err)
+		require.True(t, applies)
+	}
+	require.Equal(t, "(taskType = magic) or (name = Harry)", compiled.String())
+}
+
+func TestCompileWithCoercion(t *testing.T) {
+	obj := TestObject{Id: 42, HouseIds: []int{1, 2}, Nicknames: []string{"Harry"}}
+	condition := filter.And(
+		filter.In("id", []any{7.0, "42"}),
+		filter.ArraysOverlap("houseIds", []any{"2", 3.0}),
+		filter.ArrayIsContained("nicknames", []any{"Harry", "Potter"}),
+	)
+	applies, err := FilterApplies(obj, condition)
+	require.NoError(t, err)
+	require.False(t, applies)
+
+	compiled, err := Compile(condition, WithCoercion())
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		applies, err := compiled.Applies(obj)
+		require.NoError(t, err)
+		require.True(t, applies)
+	}
+	applies, err = compiled.Applies(map[string]any{"id": 42.0, "houseIds": []any{3.0}, "nicknames": []any{"Harry"}})
+	require.NoError(t, err)
+	require.True(t, applies)
+	applies, err = compiled.Applies(map[string]any{"id": "42", "houseIds": []int{3}, "nicknames": nil})
+	require.NoError(t, err)
+	require.False(t, applies)
+
+	compiled, err = Compile(filter.Equals("id", 42.5), WithCoercion())
+	require.NoError(t, err)
+	_, err = compiled.Applies(obj)
+	require.EqualError(t, err, "cannot coerce value of field 'id': cannot convert 42.5 into int without losing information")
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition filter.Condition
+		err       string
+	}{
+		{
+			name:      "unknown condition",
+			condition: filter.Where(filter.Not(&unknownCondition{})),
+			err:       "unknown condition: UnknownCondition",
+		},
+		{
+			name:      "and with a single condition",
+			condition: filter.And(filter.Equals("id", 1)),
+			err:       "AND condition must have at least two conditions",
+		},
+		{
+			name:      "or with a single condition",
+			condition: filter.Or(filter.Equals("id", 1)),
+			err:       "OR condition must have at least two conditions",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test := test
+
+			compiled, err := Compile(test.condition)
+
+			require.EqualError(t, err, test.err)
+			require.Nil(t, compiled)
+		})
+	}
+}
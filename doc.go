@@ -0,0 +1,10 @@
+// Package filterobject evaluates the conditions of the github.com/xafelium/filter package
+// against Go objects. Fields are addressed by paths like "childObject.name",
+// `labels["team-a"]` or "items[*].sku". Names in dotted paths match struct fields and map
+// keys in camel case, while keys in brackets only match map keys exactly.
+//
+// FilterApplies evaluates a condition once. Conditions which are evaluated repeatedly should
+// be compiled with Compile. Compiled conditions and conditions evaluated with
+// FilterAppliesObserved can be observed with an Observer, e.g. to collect Metrics;
+// FilterApplies, Find and Collection do not notify observers.
+package filterobject
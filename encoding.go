@@ -0,0 +1,774 @@
+package filterobject
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"github.com/xafelium/filter"
+	"gopkg.in/yaml.v3"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// conditionDocument is the serialized form of a condition. Junctions have conditions,
+// Not, Group and Where have a condition and all other conditions have a field and,
+// except IsNil, NotNil, IsEmpty and NotEmpty, a value. The value of Regex and NotRegex is
+// their pattern, of length conditions their length and of key conditions their keys. The
+// case-insensitive variants of text conditions have own names like "startsWithIgnoreCase".
+type conditionDocument struct {
+	Type       string               `json:"type" yaml:"type"`
+	Field      string               `json:"field,omitempty" yaml:"field,omitempty"`
+	Value      *operand             `json:"value,omitempty" yaml:"value,omitempty"`
+	Condition  *conditionDocument   `json:"condition,omitempty" yaml:"condition,omitempty"`
+	Conditions []*conditionDocument `json:"conditions,omitempty" yaml:"conditions,omitempty"`
+}
+
+// conditionTypeNames contains the serialized names of the condition types.
+var conditionTypeNames = map[string]string{
+	filter.WhereConditionType:              "where",
+	filter.GroupConditionType:              "group",
+	filter.NotConditionType:                "not",
+	filter.AndConditionType:                "and",
+	filter.OrConditionType:                 "or",
+	filter.EqualsConditionType:             "equals",
+	filter.NotEqualsConditionType:          "notEquals",
+	filter.GreaterThanConditionType:        "greaterThan",
+	filter.GreaterThanOrEqualConditionType: "greaterThanOrEqual",
+	filter.LowerThanConditionType:          "lowerThan",
+	filter.LowerThanOrEqualConditionType:   "lowerThanOrEqual",
+	filter.InConditionType:                 "in",
+	filter.IsNilConditionType:              "isNil",
+	filter.NotNilConditionType:             "notNil",
+	filter.ContainsConditionType:           "contains",
+	filter.ArrayContainsConditionType:      "arrayContains",
+	filter.ArrayContainsArrayConditionType: "arrayContainsArray",
+	filter.ArrayIsContainedConditionType:   "arrayIsContained",
+	filter.ArraysOverlapConditionType:      "arraysOverlap",
+	filter.OverlapsConditionType:           "overlaps",
+	filter.RegexConditionType:              "regex",
+	filter.NotRegexConditionType:           "notRegex",
+	LengthEqualsConditionType:              "lengthEquals",
+	LengthGreaterThanConditionType:         "lengthGreaterThan",
+	LengthLowerThanConditionType:           "lengthLowerThan",
+	IsEmptyConditionType:                   "isEmpty",
+	NotEmptyConditionType:                  "notEmpty",
+	HasKeyConditionType:                    "hasKey",
+	HasAnyKeyConditionType:                 "hasAnyKey",
+	HasAllKeysConditionType:                "hasAllKeys",
+	StartsWithConditionType:                "startsWith",
+	EndsWithConditionType:                  "endsWith",
+	ContainsTextConditionType:              "containsText",
+}
+
+// valueConditions creates the conditions which consist of a field and a value by their serialized name.
+var valueConditions = map[string]func(field string, value any) filter.Condition{
+	"equals":             filter.Equals,
+	"notEquals":          filter.NotEquals,
+	"greaterThan":        filter.GreaterThan,
+	"greaterThanOrEqual": filter.GreaterThanOrEqual,
+	"lowerThan":          filter.LowerThan,
+	"lowerThanOrEqual":   filter.LowerThanOrEqual,
+	"in":                 filter.In,
+	"arrayContains":      filter.ArrayContains,
+	"arrayContainsArray": filter.ArrayContainsArray,
+	"arrayIsContained":   filter.ArrayIsContained,
+	"arraysOverlap":      filter.ArraysOverlap,
+	"overlaps":           filter.Overlaps,
+}
+
+// stringConditions creates the conditions which consist of a field and a string by their serialized name.
+var stringConditions = map[string]func(field string, value string) filter.Condition{
+	"contains":               filter.Contains,
+	"regex":                  filter.Regex,
+	"notRegex":               filter.NotRegex,
+	"hasKey":                 HasKey,
+	"startsWith":             StartsWith,
+	"startsWithIgnoreCase":   StartsWithIgnoreCase,
+	"endsWith":               EndsWith,
+	"endsWithIgnoreCase":     EndsWithIgnoreCase,
+	"containsText":           ContainsText,
+	"containsTextIgnoreCase": ContainsTextIgnoreCase,
+}
+
+// keysConditions creates the conditions which consist of a field and a list of map keys by their serialized name.
+var keysConditions = map[string]func(field string, keys ...string) filter.Condition{
+	"hasAnyKey":  HasAnyKey,
+	"hasAllKeys": HasAllKeys,
+}
+
+// lengthConditions creates the conditions which consist of a field and a length by their serialized name.
+var lengthConditions = map[string]func(field string, length int) filter.Condition{
+	"lengthEquals":      LengthEquals,
+	"lengthGreaterThan": LengthGreaterThan,
+	"lengthLowerThan":   LengthLowerThan,
+}
+
+type decodeOptions struct {
+	targetType reflect.Type
+}
+
+// DecodeOption configures the decoding of conditions.
+type DecodeOption func(o *decodeOptions)
+
+// WithTargetType coerces the values of decoded conditions into the types of the fields of
+// objects of type t as CoerceCondition does.
+func WithTargetType(t reflect.Type) DecodeOption {
+	return func(o *decodeOptions) {
+		o.targetType = t
+	}
+}
+
+// MarshalCondition returns the JSON representation of the condition, e.g.
+// {"type":"and","conditions":[{"type":"equals","field":"id","value":1},{"type":"isNil","field":"name"}]}.
+// Strings, booleans, integers, floats, nil and slices are written as JSON values, floats always
+// with a decimal point or an exponent. Times, durations, relative times, TimeValue, TimeOfDay and
+// math/big numbers are written as objects like {"type":"time","value":"2024-01-02T03:04:05Z"}.
+// Values implementing encoding.TextMarshaler are written as strings.
+func MarshalCondition(condition filter.Condition) ([]byte, error) {
+	doc, err := encodeCondition(condition)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(doc)
+}
+
+// UnmarshalCondition decodes a condition from its JSON representation. Integers are decoded
+// as int, or uint64 if they exceed int, numbers with a decimal point or an exponent as float64
+// and lists as []any.
+func UnmarshalCondition(data []byte, opts ...DecodeOption) (filter.Condition, error) {
+	var doc conditionDocument
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid condition: %w", err)
+	}
+	return decodeDocument(&doc, opts)
+}
+
+// MarshalConditionYAML returns the YAML representation of the condition. It has the same
+// structure as the JSON representation.
+func MarshalConditionYAML(condition filter.Condition) ([]byte, error) {
+	doc, err := encodeCondition(condition)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(doc)
+}
+
+// UnmarshalConditionYAML decodes a condition from its YAML representation.
+// Values are decoded like UnmarshalCondition does, depending on their YAML tags.
+func UnmarshalConditionYAML(data []byte, opts ...DecodeOption) (filter.Condition, error) {
+	var doc conditionDocument
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid condition: %w", err)
+	}
+	return decodeDocument(&doc, opts)
+}
+
+func decodeDocument(doc *conditionDocument, opts []DecodeOption) (filter.Condition, error) {
+	options := &decodeOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	condition, err := decodeCondition(doc)
+	if err != nil {
+		return nil, err
+	}
+	if options.targetType != nil {
+		return CoerceCondition(condition, options.targetType)
+	}
+	return condition, nil
+}
+
+func encodeCondition(condition filter.Condition) (*conditionDocument, error) {
+	if condition == nil {
+		return nil, fmt.Errorf("condition must not be nil")
+	}
+	name, ok := conditionTypeNames[condition.Type()]
+	if !ok {
+		return nil, fmt.Errorf("unknown condition: %s", condition.Type())
+	}
+	doc := &conditionDocument{Type: name}
+	var err error
+	switch c := condition.(type) {
+	case *filter.WhereCondition:
+		if c.Condition != nil {
+			doc.Condition, err = encodeCondition(c.Condition)
+		}
+	case *filter.GroupCondition:
+		doc.Condition, err = encodeCondition(c.Condition)
+	case *filter.NotCondition:
+		doc.Condition, err = encodeCondition(c.Condition)
+	case *filter.AndCondition:
+		doc.Conditions, err = encodeConditions(c.Conditions)
+	case *filter.OrCondition:
+		doc.Conditions, err = encodeConditions(c.Conditions)
+	case *filter.IsNilCondition:
+		doc.Field = c.Field
+	case *filter.NotNilCondition:
+		doc.Field = c.Field
+	case *filter.ContainsCondition:
+		doc.Field, doc.Value = c.Field, &operand{value: c.Value}
+	case *filter.RegexCondition:
+		doc.Field, doc.Value = c.Field, &operand{value: c.Expression}
+	case *filter.NotRegexCondition:
+		doc.Field, doc.Value = c.Field, &operand{value: c.Expression}
+	case *LengthEqualsCondition:
+		doc.Field, doc.Value = c.Field, &operand{value: c.Length}
+	case *LengthGreaterThanCondition:
+		doc.Field, doc.Value = c.Field, &operand{value: c.Length}
+	case *LengthLowerThanCondition:
+		doc.Field, doc.Value = c.Field, &operand{value: c.Length}
+	case *IsEmptyCondition:
+		doc.Field = c.Field
+	case *NotEmptyCondition:
+		doc.Field = c.Field
+	case *HasKeyCondition:
+		doc.Field, doc.Value = c.Field, &operand{value: c.Key}
+	case *HasAnyKeyCondition:
+		doc.Field, doc.Value = c.Field, &operand{value: c.Keys}
+	case *HasAllKeysCondition:
+		doc.Field, doc.Value = c.Field, &operand{value: c.Keys}
+	case *StartsWithCondition:
+		doc.Type, doc.Field, doc.Value = ignoreCaseName(name, c.IgnoreCase), c.Field, &operand{value: c.Value}
+	case *EndsWithCondition:
+		doc.Type, doc.Field, doc.Value = ignoreCaseName(name, c.IgnoreCase), c.Field, &operand{value: c.Value}
+	case *ContainsTextCondition:
+		doc.Type, doc.Field, doc.Value = ignoreCaseName(name, c.IgnoreCase), c.Field, &operand{value: c.Value}
+	default:
+		field, value, ok := fieldValue(condition)
+		if !ok {
+			return nil, fmt.Errorf("unknown condition: %s", condition.Type())
+		}
+		doc.Field, doc.Value = field, &operand{value: value}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if doc.Value != nil {
+		// Values are encoded eagerly, so invalid values are reported with their field.
+		if _, err := encodeValue(reflect.ValueOf(doc.Value.value)); err != nil {
+			return nil, fmt.Errorf("cannot encode value of field '%s': %w", doc.Field, err)
+		}
+	}
+	return doc, nil
+}
+
+func encodeConditions(conditions []filter.Condition) ([]*conditionDocument, error) {
+	docs := make([]*conditionDocument, 0, len(conditions))
+	for _, c := range conditions {
+		doc, err := encodeCondition(c)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+// ignoreCaseName returns the serialized name of the case-insensitive variant of text conditions.
+func ignoreCaseName(name string, ignoreCase bool) string {
+	if ignoreCase {
+		return name + "IgnoreCase"
+	}
+	return name
+}
+
+// fieldValue returns the field and the value of conditions which consist of both.
+func fieldValue(condition filter.Condition) (string, any, bool) {
+	switch c := condition.(type) {
+	case *filter.EqualsCondition:
+		return c.Field, c.Value, true
+	case *filter.NotEqualsCondition:
+		return c.Field, c.Value, true
+	case *filter.GreaterThanCondition:
+		return c.Field, c.Value, true
+	case *filter.GreaterThanOrEqualCondition:
+		return c.Field, c.Value, true
+	case *filter.LowerThanCondition:
+		return c.Field, c.Value, true
+	case *filter.LowerThanOrEqualCondition:
+		return c.Field, c.Value, true
+	case *filter.InCondition:
+		return c.Field, c.Value, true
+	case *filter.ArrayContainsCondition:
+		return c.Field, c.Value, true
+	case *filter.ArrayContainsArrayCondition:
+		return c.Field, c.Value, true
+	case *filter.ArrayIsContainedCondition:
+		return c.Field, c.Value, true
+	case *filter.ArraysOverlapCondition:
+		return c.Field, c.Value, true
+	case *filter.OverlapsCondition:
+		return c.Field, c.Value, true
+	}
+	return "", nil, false
+}
+
+func decodeCondition(doc *conditionDocument) (filter.Condition, error) {
+	if doc == nil {
+		return nil, fmt.Errorf("condition must not be null")
+	}
+	switch doc.Type {
+	case "where", "group", "not":
+		if doc.Field != "" || doc.Value != nil || doc.Conditions != nil {
+			return nil, fmt.Errorf("%s condition must only have a condition", doc.Type)
+		}
+		if doc.Condition == nil {
+			if doc.Type == "where" {
+				return filter.Where(nil), nil
+			}
+			return nil, fmt.Errorf("%s condition must have a condition", doc.Type)
+		}
+		c, err := decodeCondition(doc.Condition)
+		if err != nil {
+			return nil, err
+		}
+		switch doc.Type {
+		case "where":
+			return filter.Where(c), nil
+		case "group":
+			return filter.Group(c), nil
+		default:
+			return filter.Not(c), nil
+		}
+	case "and", "or":
+		if doc.Field != "" || doc.Value != nil || doc.Condition != nil {
+			return nil, fmt.Errorf("%s condition must only have conditions", doc.Type)
+		}
+		if len(doc.Conditions) < 2 {
+			return nil, fmt.Errorf("%s condition must have at least two conditions", doc.Type)
+		}
+		conditions := make([]filter.Condition, 0, len(doc.Conditions))
+		for _, d := range doc.Conditions {
+			c, err := decodeCondition(d)
+			if err != nil {
+				return nil, err
+			}
+			conditions = append(conditions, c)
+		}
+		if doc.Type == "and" {
+			return filter.And(conditions...), nil
+		}
+		return filter.Or(conditions...), nil
+	}
+
+	if doc.Condition != nil || doc.Conditions != nil {
+		return nil, fmt.Errorf("%s condition must not have conditions", doc.Type)
+	}
+	if doc.Field == "" {
+		return nil, fmt.Errorf("%s condition must have a field", doc.Type)
+	}
+	var value any
+	if doc.Value != nil {
+		value = doc.Value.value
+	}
+	switch doc.Type {
+	case "isNil", "notNil", "isEmpty", "notEmpty":
+		if doc.Value != nil {
+			return nil, fmt.Errorf("%s condition must not have a value", doc.Type)
+		}
+		switch doc.Type {
+		case "isNil":
+			return filter.IsNil(doc.Field), nil
+		case "notNil":
+			return filter.NotNil(doc.Field), nil
+		case "isEmpty":
+			return IsEmpty(doc.Field), nil
+		}
+		return NotEmpty(doc.Field), nil
+	}
+	if create, ok := lengthConditions[doc.Type]; ok {
+		length, ok := value.(int)
+		if !ok || length < 0 {
+			return nil, fmt.Errorf("%s condition must have a non-negative integer value", doc.Type)
+		}
+		return create(doc.Field, length), nil
+	}
+	if create, ok := keysConditions[doc.Type]; ok {
+		values, ok := value.([]any)
+		if !ok {
+			return nil, fmt.Errorf("%s condition must have a list of strings as value", doc.Type)
+		}
+		var keys []string
+		for _, v := range values {
+			key, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s condition must have a list of strings as value", doc.Type)
+			}
+			keys = append(keys, key)
+		}
+		return create(doc.Field, keys...), nil
+	}
+	if create, ok := stringConditions[doc.Type]; ok {
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s condition must have a string value", doc.Type)
+		}
+		return create(doc.Field, s), nil
+	}
+	if create, ok := valueConditions[doc.Type]; ok {
+		return create(doc.Field, value), nil
+	}
+	return nil, fmt.Errorf("unknown condition type: %s", doc.Type)
+}
+
+// operand is a value of a condition which keeps its type when it is serialized.
+type operand struct {
+	value any
+}
+
+// typedValue is the serialized form of values which have no JSON or YAML representation.
+type typedValue struct {
+	Type      string `json:"type" yaml:"type"`
+	Value     string `json:"value" yaml:"value"`
+	Unit      string `json:"unit,omitempty" yaml:"unit,omitempty"`
+	Location  string `json:"location,omitempty" yaml:"location,omitempty"`
+	Precision uint   `json:"precision,omitempty" yaml:"precision,omitempty"`
+}
+
+// floatValue is a float which is always written with a decimal point or an exponent,
+// so it is decoded as float again.
+type floatValue float64
+
+func (f floatValue) String() string {
+	s := strconv.FormatFloat(float64(f), 'g', -1, 64)
+	if !strings.ContainsAny(s, ".e") {
+		s += ".0"
+	}
+	return s
+}
+
+// MarshalJSON implements json.Marshaler.
+func (f floatValue) MarshalJSON() ([]byte, error) {
+	return []byte(f.String()), nil
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (f floatValue) MarshalYAML() (any, error) {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: f.String()}, nil
+}
+
+var timeUnitNames = map[TimeUnit]string{
+	Nanosecond: "nanosecond",
+	Second:     "second",
+	Minute:     "minute",
+	Hour:       "hour",
+	Day:        "day",
+	Week:       "week",
+	Month:      "month",
+	Year:       "year",
+}
+
+// encodeValue converts v into a value which is written by the JSON and YAML encoders in
+// a form which is decoded into a value of the same type again.
+func encodeValue(v reflect.Value) (any, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+	if isBigNumber(v) {
+		p := reflect.New(v.Type())
+		p.Elem().Set(v)
+		v = p
+	}
+	if v.CanInterface() {
+		switch value := v.Interface().(type) {
+		case time.Time:
+			return typedValue{Type: "time", Value: value.Format(time.RFC3339Nano)}, nil
+		case time.Duration:
+			return typedValue{Type: "duration", Value: value.String()}, nil
+		case RelativeTime:
+			loc, err := locationName(value.Location)
+			return typedValue{Type: "relativeTime", Value: value.String(), Location: loc}, err
+		case TimeValue:
+			loc, err := locationName(value.Location)
+			return typedValue{Type: "timeValue", Value: value.Time.Format(time.RFC3339Nano), Unit: timeUnitNames[value.Unit], Location: loc}, err
+		case TimeOfDay:
+			loc, err := locationName(value.Location)
+			return typedValue{Type: "timeOfDay", Value: fmt.Sprintf("%02d:%02d:%02d", value.Hour, value.Minute, value.Second), Location: loc}, err
+		case *big.Int:
+			if value != nil {
+				return typedValue{Type: "bigInt", Value: value.String()}, nil
+			}
+		case *big.Float:
+			if value != nil {
+				return typedValue{Type: "bigFloat", Value: value.Text('g', -1), Precision: value.Prec()}, nil
+			}
+		case *big.Rat:
+			if value != nil {
+				return typedValue{Type: "bigRat", Value: value.RatString()}, nil
+			}
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return encodeValue(v.Elem())
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		if math.IsInf(v.Float(), 0) || math.IsNaN(v.Float()) {
+			return nil, fmt.Errorf("value %v cannot be encoded", v.Float())
+		}
+		return floatValue(v.Float()), nil
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Slice, reflect.Array:
+		values := make([]any, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			value, err := encodeValue(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return values, nil
+	}
+	for _, receiver := range methodReceivers(v) {
+		if m, ok := receiver.Interface().(encoding.TextMarshaler); ok {
+			text, err := m.MarshalText()
+			if err != nil {
+				return nil, fmt.Errorf("cannot encode value of type %s: %w", v.Type(), err)
+			}
+			return string(text), nil
+		}
+	}
+	return nil, fmt.Errorf("values of type %s cannot be encoded", v.Type())
+}
+
+func locationName(loc *time.Location) (string, error) {
+	if loc == nil {
+		return "", nil
+	}
+	if loc.String() == "" {
+		return "", fmt.Errorf("locations without a name cannot be encoded")
+	}
+	return loc.String(), nil
+}
+
+func decodeTypedValue(t typedValue) (any, error) {
+	var loc *time.Location
+	if t.Location != "" {
+		var err error
+		if loc, err = time.LoadLocation(t.Location); err != nil {
+			return nil, fmt.Errorf("invalid location %q: %w", t.Location, err)
+		}
+	}
+	switch t.Type {
+	case "time":
+		parsed, err := time.Parse(time.RFC3339Nano, t.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time %q: %w", t.Value, err)
+		}
+		return parsed, nil
+	case "duration":
+		d, err := time.ParseDuration(t.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration %q: %w", t.Value, err)
+		}
+		return d, nil
+	case "relativeTime":
+		r, err := ParseRelativeTime(t.Value)
+		if err != nil {
+			return nil, err
+		}
+		r.Location = loc
+		return r, nil
+	case "timeValue":
+		parsed, err := time.Parse(time.RFC3339Nano, t.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time %q: %w", t.Value, err)
+		}
+		for unit, name := range timeUnitNames {
+			if name == t.Unit {
+				return TruncatedTime(parsed, unit, loc), nil
+			}
+		}
+		return nil, fmt.Errorf("invalid time unit %q", t.Unit)
+	case "timeOfDay":
+		parsed, err := time.Parse(timeOfDayLayout, t.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time of day %q: %w", t.Value, err)
+		}
+		return TimeOfDay{Hour: parsed.Hour(), Minute: parsed.Minute(), Second: parsed.Second(), Location: loc}, nil
+	case "bigInt":
+		i, ok := new(big.Int).SetString(t.Value, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid integer %q", t.Value)
+		}
+		return i, nil
+	case "bigFloat":
+		f, _, err := big.ParseFloat(t.Value, 10, t.Precision, big.ToNearestEven)
+		if err != nil {
+			return nil, fmt.Errorf("invalid float %q: %w", t.Value, err)
+		}
+		return f, nil
+	case "bigRat":
+		r, ok := new(big.Rat).SetString(t.Value)
+		if !ok {
+			return nil, fmt.Errorf("invalid rational number %q", t.Value)
+		}
+		return r, nil
+	}
+	return nil, fmt.Errorf("unknown value type: %s", t.Type)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (o *operand) MarshalJSON() ([]byte, error) {
+	v, err := encodeValue(reflect.ValueOf(o.value))
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (o *operand) UnmarshalJSON(data []byte) error {
+	v, err := decodeJSONValue(data)
+	if err != nil {
+		return err
+	}
+	o.value = v
+	return nil
+}
+
+func decodeJSONValue(data []byte) (any, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, fmt.Errorf("missing value")
+	}
+	switch data[0] {
+	case '{':
+		var t typedValue
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&t); err != nil {
+			return nil, err
+		}
+		return decodeTypedValue(t)
+	case '[':
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil, err
+		}
+		values := make([]any, 0, len(items))
+		for _, item := range items {
+			v, err := decodeJSONValue(item)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v)
+		}
+		return values, nil
+	case '"':
+		var s string
+		err := json.Unmarshal(data, &s)
+		return s, err
+	case 't', 'f':
+		var b bool
+		err := json.Unmarshal(data, &b)
+		return b, err
+	case 'n':
+		return nil, nil
+	}
+	return parseJSONNumber(string(data))
+}
+
+// parseJSONNumber parses a JSON number into an int, uint64 or float64.
+func parseJSONNumber(s string) (any, error) {
+	if strings.ContainsAny(s, ".eE") {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %s", s)
+		}
+		return f, nil
+	}
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if i >= math.MinInt && i <= math.MaxInt {
+			return int(i), nil
+		}
+		return i, nil
+	}
+	if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return u, nil
+	}
+	return nil, fmt.Errorf("invalid number %s", s)
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (o *operand) MarshalYAML() (any, error) {
+	return encodeValue(reflect.ValueOf(o.value))
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (o *operand) UnmarshalYAML(node *yaml.Node) error {
+	v, err := decodeYAMLValue(node)
+	if err != nil {
+		return err
+	}
+	o.value = v
+	return nil
+}
+
+func decodeYAMLValue(node *yaml.Node) (any, error) {
+	switch node.Kind {
+	case yaml.AliasNode:
+		return decodeYAMLValue(node.Alias)
+	case yaml.MappingNode:
+		var t typedValue
+		if err := node.Decode(&t); err != nil {
+			return nil, err
+		}
+		return decodeTypedValue(t)
+	case yaml.SequenceNode:
+		values := make([]any, 0, len(node.Content))
+		for _, item := range node.Content {
+			v, err := decodeYAMLValue(item)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v)
+		}
+		return values, nil
+	case yaml.ScalarNode:
+		switch node.ShortTag() {
+		case "!!null":
+			return nil, nil
+		case "!!bool":
+			var b bool
+			err := node.Decode(&b)
+			return b, err
+		case "!!int":
+			var i int
+			if err := node.Decode(&i); err == nil {
+				return i, nil
+			}
+			var u uint64
+			if err := node.Decode(&u); err != nil {
+				return nil, fmt.Errorf("invalid number %s", node.Value)
+			}
+			return u, nil
+		case "!!float":
+			var f float64
+			err := node.Decode(&f)
+			return f, err
+		case "!!str", "!!timestamp":
+			return node.Value, nil
+		}
+	}
+	return nil, fmt.Errorf("line %d: unsupported value %q", node.Line, node.Value)
+}
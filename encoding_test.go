@@ -0,0 +1,292 @@
+package filterobject
+
+import (
+	"github.com/stretchr/testify/require"
+	"github.com/xafelium/filter"
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMarshalCondition(t *testing.T) {
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+	tests := []struct {
+		name      string
+		condition filter.Condition
+		expected  string
+	}{
+		{
+			name:      "empty where",
+			condition: filter.Where(nil),
+			expected:  `{"type":"where"}`,
+		},
+		{
+			name: "junctions",
+			condition: filter.Where(filter.And(
+				filter.Equals("id", 1),
+				filter.Or(filter.IsNil("name"), filter.Not(filter.Group(filter.NotNil("childObject")))),
+			)),
+			expected: `{"type":"where","condition":{"type":"and","conditions":[` +
+				`{"type":"equals","field":"id","value":1},` +
+				`{"type":"or","conditions":[{"type":"isNil","field":"name"},{"type":"not","condition":{"type":"group","condition":{"type":"notNil","field":"childObject"}}}]}]}}`,
+		},
+		{
+			name: "scalar values",
+			condition: filter.And(
+				filter.Equals("a", 2.0),
+				filter.NotEquals("b", "x"),
+				filter.GreaterThan("c", 1.5e100),
+				filter.GreaterThanOrEqual("d", true),
+				filter.LowerThan("e", nil),
+				filter.LowerThanOrEqual("f", uint64(1<<63)),
+			),
+			expected: `{"type":"and","conditions":[` +
+				`{"type":"equals","field":"a","value":2.0},` +
+				`{"type":"notEquals","field":"b","value":"x"},` +
+				`{"type":"greaterThan","field":"c","value":1.5e+100},` +
+				`{"type":"greaterThanOrEqual","field":"d","value":true},` +
+				`{"type":"lowerThan","field":"e","value":null},` +
+				`{"type":"lowerThanOrEqual","field":"f","value":9223372036854775808}]}`,
+		},
+		{
+			name: "typed values",
+			condition: filter.And(
+				filter.Equals("createdAt", createdAt),
+				filter.LowerThan("timeout", 90*time.Second),
+				filter.GreaterThan("createdAt", RelativeTime{Days: -1, StartOf: Day, Location: time.UTC}),
+				filter.Equals("createdAt", TruncatedTime(createdAt, Hour, nil)),
+				filter.GreaterThan("createdAt", TimeOfDay{Hour: 8, Minute: 30}),
+				filter.Equals("total", big.NewInt(7)),
+				filter.Equals("amount", big.NewRat(1, 3)),
+			),
+			expected: `{"type":"and","conditions":[` +
+				`{"type":"equals","field":"createdAt","value":{"type":"time","value":"2024-01-02T03:04:05.000000006Z"}},` +
+				`{"type":"lowerThan","field":"timeout","value":{"type":"duration","value":"1m30s"}},` +
+				`{"type":"greaterThan","field":"createdAt","value":{"type":"relativeTime","value":"now-1d/d","location":"UTC"}},` +
+				`{"type":"equals","field":"createdAt","value":{"type":"timeValue","value":"2024-01-02T03:04:05.000000006Z","unit":"hour"}},` +
+				`{"type":"greaterThan","field":"createdAt","value":{"type":"timeOfDay","value":"08:30:00"}},` +
+				`{"type":"equals","field":"total","value":{"type":"bigInt","value":"7"}},` +
+				`{"type":"equals","field":"amount","value":{"type":"bigRat","value":"1/3"}}]}`,
+		},
+		{
+			name: "lists and strings",
+			condition: filter.And(
+				filter.In("id", []any{1, 2.5, "x", []any{}}),
+				filter.ArrayContains("nicknames", "a"),
+				filter.ArrayContainsArray("nicknames", []any{"a"}),
+				filter.ArrayIsContained("houseIds", []any{1}),
+				filter.ArraysOverlap("houseIds", []any{2}),
+				filter.Overlaps("period", []any{3}),
+				filter.Contains("name", "o"),
+				filter.Regex("name", `^\d`),
+				filter.NotRegex("name", "x"),
+			),
+			expected: `{"type":"and","conditions":[` +
+				`{"type":"in","field":"id","value":[1,2.5,"x",[]]},` +
+				`{"type":"arrayContains","field":"nicknames","value":"a"},` +
+				`{"type":"arrayContainsArray","field":"nicknames","value":["a"]},` +
+				`{"type":"arrayIsContained","field":"houseIds","value":[1]},` +
+				`{"type":"arraysOverlap","field":"houseIds","value":[2]},` +
+				`{"type":"overlaps","field":"period","value":[3]},` +
+				`{"type":"contains","field":"name","value":"o"},` +
+				`{"type":"regex","field":"name","value":"^\\d"},` +
+				`{"type":"notRegex","field":"name","value":"x"}]}`,
+		},
+		{
+			name: "lengths",
+			condition: filter.And(
+				LengthEquals("nicknames", 0),
+				LengthGreaterThan("houseIds", 2),
+				LengthLowerThan("name", 50),
+				IsEmpty("tags"),
+				NotEmpty("labels"),
+			),
+			expected: `{"type":"and","conditions":[` +
+				`{"type":"lengthEquals","field":"nicknames","value":0},` +
+				`{"type":"lengthGreaterThan","field":"houseIds","value":2},` +
+				`{"type":"lengthLowerThan","field":"name","value":50},` +
+				`{"type":"isEmpty","field":"tags"},` +
+				`{"type":"notEmpty","field":"labels"}]}`,
+		},
+		{
+			name:      "keys",
+			condition: filter.And(HasKey("labels", "env"), HasAnyKey("labels", "a", "b"), HasAllKeys(`meta["labels"]`, "c")),
+			expected: `{"type":"and","conditions":[` +
+				`{"type":"hasKey","field":"labels","value":"env"},` +
+				`{"type":"hasAnyKey","field":"labels","value":["a","b"]},` +
+				`{"type":"hasAllKeys","field":"meta[\"labels\"]","value":["c"]}]}`,
+		},
+		{
+			name: "texts",
+			condition: filter.And(
+				StartsWith("name", "a"),
+				StartsWithIgnoreCase("name", "b"),
+				EndsWith("name", "c"),
+				EndsWithIgnoreCase("name", "d"),
+				ContainsText("tags", "e"),
+				ContainsTextIgnoreCase("tags", "f"),
+			),
+			expected: `{"type":"and","conditions":[` +
+				`{"type":"startsWith","field":"name","value":"a"},` +
+				`{"type":"startsWithIgnoreCase","field":"name","value":"b"},` +
+				`{"type":"endsWith","field":"name","value":"c"},` +
+				`{"type":"endsWithIgnoreCase","field":"name","value":"d"},` +
+				`{"type":"containsText","field":"tags","value":"e"},` +
+				`{"type":"containsTextIgnoreCase","field":"tags","value":"f"}]}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := MarshalCondition(test.condition)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, string(data))
+
+			decoded, err := UnmarshalCondition(data)
+			require.NoError(t, err)
+			require.Equal(t, test.condition, decoded)
+
+			data, err = MarshalConditionYAML(test.condition)
+			require.NoError(t, err)
+			decoded, err = UnmarshalConditionYAML(data)
+			require.NoError(t, err)
+			require.Equal(t, test.condition, decoded)
+		})
+	}
+}
+
+func TestMarshalConditionValueTypes(t *testing.T) {
+	condition := filter.And(
+		filter.In("id", []int{1, 2}),
+		filter.Equals("priority", PriorityHigh),
+		filter.Equals("size", uint8(3)),
+		filter.Equals("weight", float32(0.5)),
+	)
+	expected := filter.And(
+		filter.In("id", []any{1, 2}),
+		filter.Equals("priority", "high"),
+		filter.Equals("size", 3),
+		filter.Equals("weight", 0.5),
+	)
+	data, err := MarshalCondition(condition)
+	require.NoError(t, err)
+	decoded, err := UnmarshalCondition(data)
+	require.NoError(t, err)
+	require.Equal(t, expected, decoded)
+
+	data, err = MarshalConditionYAML(condition)
+	require.NoError(t, err)
+	decoded, err = UnmarshalConditionYAML(data)
+	require.NoError(t, err)
+	require.Equal(t, expected, decoded)
+
+	ratio := new(big.Float).SetPrec(100).SetFloat64(0.1)
+	data, err = MarshalCondition(filter.Equals("ratio", ratio))
+	require.NoError(t, err)
+	decoded, err = UnmarshalCondition(data)
+	require.NoError(t, err)
+	decodedRatio := decoded.(*filter.EqualsCondition).Value.(*big.Float)
+	require.Equal(t, uint(100), decodedRatio.Prec())
+	require.Zero(t, ratio.Cmp(decodedRatio))
+}
+
+func TestUnmarshalConditionYAML(t *testing.T) {
+	condition, err := UnmarshalConditionYAML([]byte(`
+type: and
+conditions:
+  - type: in
+    field: id
+    value: [1, 2.0, 0x10, "3"]
+  - type: greaterThan
+    field: createdAt
+    value:
+      type: relativeTime
+      value: now-7d
+  - type: equals
+    field: childObject
+    value: null
+`))
+	require.NoError(t, err)
+	require.Equal(t, filter.And(
+		filter.In("id", []any{1, 2.0, 16, "3"}),
+		filter.GreaterThan("createdAt", RelativeTime{Days: -7}),
+		filter.Equals("childObject", nil),
+	), condition)
+}
+
+func TestUnmarshalConditionTargetType(t *testing.T) {
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	data := []byte(`{"type":"and","conditions":[
+		{"type":"equals","field":"id","value":1.0},
+		{"type":"in","field":"id","value":[1, 2]},
+		{"type":"arraysOverlap","field":"nicknames","value":["a", "b"]},
+		{"type":"arrayContains","field":"houseIds","value":3},
+		{"type":"greaterThanOrEqual","field":"createdAt","value":"2024-01-02T03:04:05Z"},
+		{"type":"lowerThan","field":"createdAt","value":"now"},
+		{"type":"equals","field":"childObject.taskType","value":"magic"}
+	]}`)
+	expected := filter.And(
+		filter.Equals("id", 1),
+		filter.In("id", []int{1, 2}),
+		filter.ArraysOverlap("nicknames", []string{"a", "b"}),
+		filter.ArrayContains("houseIds", 3),
+		filter.GreaterThanOrEqual("createdAt", createdAt),
+		filter.LowerThan("createdAt", "now"),
+		filter.Equals("childObject.taskType", "magic"),
+	)
+	condition, err := UnmarshalCondition(data, WithTargetType(reflect.TypeOf(TestObject{})))
+	require.NoError(t, err)
+	require.Equal(t, expected, condition)
+
+	obj := TestObject{Id: 1, Nicknames: []string{"b"}, HouseIds: []int{3}, CreatedAt: createdAt, ChildObject: &TestObject{TaskType: "magic"}}
+	for _, c := range []filter.Condition{condition, expected} {
+		applies, err := FilterApplies(obj, c)
+		require.NoError(t, err)
+		require.True(t, applies)
+	}
+
+	_, err = UnmarshalCondition([]byte(`{"type":"equals","field":"id","value":1.5}`), WithTargetType(reflect.TypeOf(TestObject{})))
+	require.EqualError(t, err, "cannot coerce value of field 'id': cannot convert 1.5 into int without losing information")
+
+	_, err = UnmarshalCondition([]byte(`{"type":"equals","field":"unknown","value":1}`), WithTargetType(reflect.TypeOf(&TestObject{})))
+	require.EqualError(t, err, "field 'unknown' was not found on object")
+}
+
+func TestConditionEncodingErrors(t *testing.T) {
+	_, err := MarshalCondition(filter.Equals("a", struct{}{}))
+	require.EqualError(t, err, "cannot encode value of field 'a': values of type struct {} cannot be encoded")
+
+	_, err = MarshalCondition(filter.In("a", []any{1, math.NaN()}))
+	require.EqualError(t, err, "cannot encode value of field 'a': value NaN cannot be encoded")
+
+	_, err = MarshalCondition(filter.GreaterThan("a", RelativeTime{Location: time.FixedZone("", 3600)}))
+	require.EqualError(t, err, "cannot encode value of field 'a': locations without a name cannot be encoded")
+
+	_, err = MarshalCondition(filter.Not(&unknownCondition{}))
+	require.EqualError(t, err, "unknown condition: UnknownCondition")
+
+	for data, expected := range map[string]string{
+		`{"type":"equals","field":"a","value":1,"other":2}`:          `invalid condition: json: unknown field "other"`,
+		`{"type":"unknown","field":"a"}`:                             "unknown condition type: unknown",
+		`{"type":"equals","value":1}`:                                "equals condition must have a field",
+		`{"type":"isNil","field":"a","value":1}`:                     "isNil condition must not have a value",
+		`{"type":"regex","field":"a","value":1}`:                     "regex condition must have a string value",
+		`{"type":"lengthEquals","field":"a","value":-1}`:             "lengthEquals condition must have a non-negative integer value",
+		`{"type":"isEmpty","field":"a","value":1}`:                   "isEmpty condition must not have a value",
+		`{"type":"hasKey","field":"a","value":["b"]}`:                "hasKey condition must have a string value",
+		`{"type":"hasAnyKey","field":"a","value":["b",1]}`:           "hasAnyKey condition must have a list of strings as value",
+		`{"type":"and","conditions":[{"type":"isNil","field":"a"}]}`: "and condition must have at least two conditions",
+		`{"type":"not"}`: "not condition must have a condition",
+		`{"type":"not","field":"a","condition":{"type":"isNil","field":"a"}}`: "not condition must only have a condition",
+		`{"type":"equals","field":"a","value":{"type":"time","value":"x"}}`:   `invalid condition: invalid time "x": parsing time "x" as "2006-01-02T15:04:05.999999999Z07:00": cannot parse "x" as "2006"`,
+		`{"type":"equals","field":"a","value":{"type":"color","value":"x"}}`:  "invalid condition: unknown value type: color",
+		`{"type":"equals","field":"a","value":1e999}`:                         "invalid condition: invalid number 1e999",
+	} {
+		_, err := UnmarshalCondition([]byte(data))
+		require.EqualError(t, err, expected, data)
+	}
+
+	_, err = UnmarshalConditionYAML([]byte("type: equals\nfield: a\nother: 1\n"))
+	require.EqualError(t, err, "invalid condition: yaml: unmarshal errors:\n  line 3: field other not found in type filterobject.conditionDocument")
+}
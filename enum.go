@@ -0,0 +1,106 @@
+package filterobject
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+type enum struct {
+	positions map[any]int
+	names     map[string]int
+}
+
+// enums contains the registered enums by their reflect.Type.
+var enums sync.Map
+
+// RegisterEnum registers the values of an enum type in ascending order.
+// Ordering conditions on fields of the enum type compare values by their position
+// instead of their lexical or numeric order. Condition values may be given as the
+// enum type, as its underlying type or as the string representation of a member.
+// Predeclared types like string or int cannot be registered as enums.
+func RegisterEnum[T comparable](values ...T) {
+	e := &enum{
+		positions: make(map[any]int, len(values)),
+		names:     make(map[string]int, len(values)),
+	}
+	for i, value := range values {
+		e.positions[value] = i
+		if name, err := stringValue(reflect.ValueOf(value)); err == nil {
+			e.names[name] = i
+		}
+	}
+	enums.Store(reflect.TypeOf((*T)(nil)).Elem(), e)
+}
+
+func lookupEnum(t reflect.Type) (*enum, bool) {
+	if isPredeclared(t) {
+		return nil, false
+	}
+	e, ok := enums.Load(t)
+	if !ok {
+		return nil, false
+	}
+	return e.(*enum), true
+}
+
+// position returns the position of v within the enum of type t.
+func (e *enum) position(t reflect.Type, v reflect.Value) (int, error) {
+	v = convertToType(v, t)
+	if v.Type() == t && v.CanInterface() {
+		if i, ok := e.positions[v.Interface()]; ok {
+			return i, nil
+		}
+	}
+	if v.Kind() == reflect.String {
+		if i, ok := e.names[v.String()]; ok {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("value %v is not a member of enum %s", v, t)
+}
+
+// member returns the member of the enum with the name.
+func (e *enum) member(name string) (reflect.Value, bool) {
+	i, ok := e.names[name]
+	if !ok {
+		return reflect.Value{}, false
+	}
+	for value, position := range e.positions {
+		if position == i {
+			return reflect.ValueOf(value), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// compareEnum compares values of registered enum types by their position.
+// The second return value is false if neither value is of a registered enum type.
+func compareEnum(field, value reflect.Value) (int, bool, error) {
+	e, ok := lookupEnum(field.Type())
+	t := field.Type()
+	if !ok {
+		if e, ok = lookupEnum(value.Type()); !ok {
+			return 0, false, nil
+		}
+		t = value.Type()
+	}
+	a, err := e.position(t, field)
+	if err != nil {
+		return 0, true, err
+	}
+	b, err := e.position(t, value)
+	if err != nil {
+		return 0, true, err
+	}
+	return compareOrdered(int64(a), int64(b)), true, nil
+}
+
+// convertToType converts v into the type t if both have the same kind, e.g. a string
+// into a named string type.
+func convertToType(v reflect.Value, t reflect.Type) reflect.Value {
+	if v.Type() != t && v.Kind() == t.Kind() && v.Type().ConvertibleTo(t) {
+		return v.Convert(t)
+	}
+	return v
+}
@@ -0,0 +1,129 @@
+package filterobject
+
+import (
+	"github.com/stretchr/testify/require"
+	"github.com/xafelium/filter"
+	"testing"
+)
+
+type Priority string
+
+const (
+	PriorityLow    Priority = "low"
+	PriorityMedium Priority = "medium"
+	PriorityHigh   Priority = "high"
+)
+
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelError
+)
+
+func (l Level) String() string {
+	return [...]string{"debug", "info", "error"}[l]
+}
+
+type Visibility bool
+
+type State string
+
+type EnumTestObject struct {
+	Priority Priority
+	Level    Level
+	Public   Visibility
+	State    State
+}
+
+func init() {
+	RegisterEnum(PriorityLow, PriorityMedium, PriorityHigh)
+	RegisterEnum(LevelDebug, LevelInfo, LevelError)
+}
+
+func TestNamedTypes(t *testing.T) {
+	var applies bool
+	var err error
+	obj := EnumTestObject{
+		Public: true,
+		State:  "active",
+	}
+
+	applies, err = applyEquals(obj, filter.Equals("state", "active"))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyIn(obj, filter.In("state", []string{"pending", "active"}))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyEquals(obj, filter.Equals("public", true))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyNotEquals(obj, filter.NotEquals("public", false))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	// Unregistered named types keep their lexical order
+	applies, err = applyGreaterThan(obj, filter.GreaterThan("state", "pending"))
+	require.NoError(t, err)
+	require.False(t, applies)
+}
+
+func TestEnumOrder(t *testing.T) {
+	var applies bool
+	var err error
+	obj := EnumTestObject{
+		Priority: PriorityMedium,
+		Level:    LevelInfo,
+	}
+
+	// Lexically "medium" is greater than "high"
+	applies, err = applyGreaterThan(obj, filter.GreaterThan("priority", PriorityHigh))
+	require.NoError(t, err)
+	require.False(t, applies)
+
+	applies, err = applyGreaterThan(obj, filter.GreaterThan("priority", "low"))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyLowerThan(obj, filter.LowerThan("priority", "high"))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyGreaterThanOrEqual(obj, filter.GreaterThanOrEqual("priority", "medium"))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyEquals(obj, filter.Equals("priority", "medium"))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	// Names of integer enums
+	applies, err = applyGreaterThan(obj, filter.GreaterThan("level", "debug"))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyLowerThan(obj, filter.LowerThan("level", 2))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyEquals(obj, filter.Equals("level", "info"))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyIn(obj, filter.In("level", []string{"info", "error"}))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	// Errors
+	applies, err = applyGreaterThan(obj, filter.GreaterThan("priority", "urgent"))
+	require.EqualError(t, err, "value urgent is not a member of enum filterobject.Priority")
+	require.False(t, applies)
+
+	applies, err = applyEquals(obj, filter.Equals("priority", "urgent"))
+	require.NoError(t, err)
+	require.False(t, applies)
+}
@@ -0,0 +1,879 @@
+package filterobject
+
+import (
+	"fmt"
+	"github.com/xafelium/filter"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// SyntaxError is an error of ParseExpression at a position of the expression.
+// Offset is the byte offset, Line and Column start at 1.
+type SyntaxError struct {
+	Offset  int
+	Line    int
+	Column  int
+	Message string
+}
+
+// Error returns the error message.
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at line %d, column %d: %s", e.Line, e.Column, e.Message)
+}
+
+// expressionOperator is an operator of the expression syntax.
+type expressionOperator struct {
+	keywords  string
+	noValue   bool
+	listValue bool
+	condition func(field string, value any) (filter.Condition, error)
+}
+
+// expressionOperators contains the operators of the expression syntax. Keywords are case-insensitive.
+var expressionOperators = []expressionOperator{
+	{keywords: "=", condition: func(field string, value any) (filter.Condition, error) {
+		return filter.Equals(field, value), nil
+	}},
+	{keywords: "==", condition: func(field string, value any) (filter.Condition, error) {
+		return filter.Equals(field, value), nil
+	}},
+	{keywords: "!=", condition: func(field string, value any) (filter.Condition, error) {
+		return filter.NotEquals(field, value), nil
+	}},
+	{keywords: "<>", condition: func(field string, value any) (filter.Condition, error) {
+		return filter.NotEquals(field, value), nil
+	}},
+	{keywords: ">", condition: func(field string, value any) (filter.Condition, error) {
+		return filter.GreaterThan(field, value), nil
+	}},
+	{keywords: ">=", condition: func(field string, value any) (filter.Condition, error) {
+		return filter.GreaterThanOrEqual(field, value), nil
+	}},
+	{keywords: "<", condition: func(field string, value any) (filter.Condition, error) {
+		return filter.LowerThan(field, value), nil
+	}},
+	{keywords: "<=", condition: func(field string, value any) (filter.Condition, error) {
+		return filter.LowerThanOrEqual(field, value), nil
+	}},
+	{keywords: "IN", listValue: true, condition: func(field string, value any) (filter.Condition, error) {
+		return filter.In(field, value), nil
+	}},
+	{keywords: "NOT IN", listValue: true, condition: func(field string, value any) (filter.Condition, error) {
+		return filter.Not(filter.In(field, value)), nil
+	}},
+	{keywords: "IS NULL", noValue: true, condition: func(field string, _ any) (filter.Condition, error) {
+		return filter.IsNil(field), nil
+	}},
+	{keywords: "IS NOT NULL", noValue: true, condition: func(field string, _ any) (filter.Condition, error) {
+		return filter.NotNil(field), nil
+	}},
+	{keywords: "CONTAINS", condition: func(field string, value any) (filter.Condition, error) {
+		return filter.ArrayContains(field, value), nil
+	}},
+	{keywords: "CONTAINS ARRAY", condition: func(field string, value any) (filter.Condition, error) {
+		return filter.ArrayContainsArray(field, value), nil
+	}},
+	{keywords: "ICONTAINS", condition: func(field string, value any) (filter.Condition, error) {
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("ICONTAINS requires a string")
+		}
+		return filter.Contains(field, s), nil
+	}},
+	{keywords: "CONTAINED IN", listValue: true, condition: func(field string, value any) (filter.Condition, error) {
+		return filter.ArrayIsContained(field, value), nil
+	}},
+	{keywords: "OVERLAPS", listValue: true, condition: func(field string, value any) (filter.Condition, error) {
+		return filter.ArraysOverlap(field, value), nil
+	}},
+	{keywords: "OVERLAP", listValue: true, condition: func(field string, value any) (filter.Condition, error) {
+		return filter.Overlaps(field, value), nil
+	}},
+	{keywords: "MATCHES", condition: func(field string, value any) (filter.Condition, error) {
+		pattern, err := expressionPattern(value)
+		if err != nil {
+			return nil, err
+		}
+		return filter.Regex(field, pattern), nil
+	}},
+	{keywords: "NOT MATCHES", condition: func(field string, value any) (filter.Condition, error) {
+		pattern, err := expressionPattern(value)
+		if err != nil {
+			return nil, err
+		}
+		return filter.NotRegex(field, pattern), nil
+	}},
+	{keywords: "LENGTH =", condition: func(field string, value any) (filter.Condition, error) {
+		length, err := expressionLength(value)
+		if err != nil {
+			return nil, err
+		}
+		return LengthEquals(field, length), nil
+	}},
+	{keywords: "LENGTH >", condition: func(field string, value any) (filter.Condition, error) {
+		length, err := expressionLength(value)
+		if err != nil {
+			return nil, err
+		}
+		return LengthGreaterThan(field, length), nil
+	}},
+	{keywords: "LENGTH <", condition: func(field string, value any) (filter.Condition, error) {
+		length, err := expressionLength(value)
+		if err != nil {
+			return nil, err
+		}
+		return LengthLowerThan(field, length), nil
+	}},
+	{keywords: "IS EMPTY", noValue: true, condition: func(field string, _ any) (filter.Condition, error) {
+		return IsEmpty(field), nil
+	}},
+	{keywords: "IS NOT EMPTY", noValue: true, condition: func(field string, _ any) (filter.Condition, error) {
+		return NotEmpty(field), nil
+	}},
+	{keywords: "HAS KEY", condition: func(field string, value any) (filter.Condition, error) {
+		key, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("HAS KEY requires a string")
+		}
+		return HasKey(field, key), nil
+	}},
+	{keywords: "HAS ANY KEY", listValue: true, condition: func(field string, value any) (filter.Condition, error) {
+		keys, err := expressionKeys("HAS ANY KEY", value)
+		if err != nil {
+			return nil, err
+		}
+		return HasAnyKey(field, keys...), nil
+	}},
+	{keywords: "HAS ALL KEYS", listValue: true, condition: func(field string, value any) (filter.Condition, error) {
+		keys, err := expressionKeys("HAS ALL KEYS", value)
+		if err != nil {
+			return nil, err
+		}
+		return HasAllKeys(field, keys...), nil
+	}},
+	{keywords: "STARTS WITH", condition: textCondition("STARTS WITH", StartsWith)},
+	{keywords: "ISTARTS WITH", condition: textCondition("ISTARTS WITH", StartsWithIgnoreCase)},
+	{keywords: "ENDS WITH", condition: textCondition("ENDS WITH", EndsWith)},
+	{keywords: "IENDS WITH", condition: textCondition("IENDS WITH", EndsWithIgnoreCase)},
+	{keywords: "CONTAINS TEXT", condition: textCondition("CONTAINS TEXT", ContainsText)},
+	{keywords: "ICONTAINS TEXT", condition: textCondition("ICONTAINS TEXT", ContainsTextIgnoreCase)},
+}
+
+// textCondition creates the conditions of operators which require a string.
+func textCondition(operator string, create func(field string, value string) filter.Condition) func(field string, value any) (filter.Condition, error) {
+	return func(field string, value any) (filter.Condition, error) {
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s requires a string", operator)
+		}
+		return create(field, s), nil
+	}
+}
+
+func expressionPattern(value any) (string, error) {
+	pattern, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("MATCHES requires a string")
+	}
+	if _, err := regexp.Compile(pattern); err != nil {
+		return "", fmt.Errorf("invalid regular expression: %w", err)
+	}
+	return pattern, nil
+}
+
+func expressionLength(value any) (int, error) {
+	length, ok := value.(int)
+	if !ok || length < 0 {
+		return 0, fmt.Errorf("LENGTH requires a non-negative integer")
+	}
+	return length, nil
+}
+
+func expressionKeys(operator string, value any) ([]string, error) {
+	values := value.([]any)
+	var keys []string
+	for _, v := range values {
+		key, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s requires a list of strings", operator)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// junctionKeywords cannot be used as field names without backquotes.
+var junctionKeywords = map[string]bool{"AND": true, "OR": true, "NOT": true}
+
+// expressionKeywords contains all keywords. Keywords are not joined with index segments, so
+// `x IN[1]` is not lexed as field.
+var expressionKeywords = map[string]bool{"AND": true, "OR": true, "NOT": true}
+
+func init() {
+	for _, op := range expressionOperators {
+		for _, keyword := range strings.Fields(op.keywords) {
+			if isIdentStart(rune(keyword[0])) {
+				expressionKeywords[keyword] = true
+			}
+		}
+	}
+}
+
+// ParseExpression parses a condition in the expression syntax, e.g.
+//
+//	taskType = "a" AND (createdAt > 2024-01-01 OR nicknames CONTAINS "x")
+//
+// Conditions are combined with AND, OR and NOT and grouped with parentheses. AND binds
+// stronger than OR. Predicates consist of a field, an operator and a value:
+//
+//	=, ==, !=, <>, >, >=, <, <=          comparisons
+//	IN, NOT IN                           membership in a list
+//	IS NULL, IS NOT NULL                 nil checks without a value
+//	IS EMPTY, IS NOT EMPTY               empty checks without a value
+//	CONTAINS, CONTAINS ARRAY             array elements, or substrings of strings
+//	ICONTAINS                            case-insensitive substrings
+//	CONTAINED IN, OVERLAPS, OVERLAP      array comparisons with a list
+//	MATCHES, NOT MATCHES                 regular expressions
+//	LENGTH =, LENGTH >, LENGTH <         lengths of strings, arrays and maps
+//	HAS KEY, HAS ANY KEY, HAS ALL KEYS   map keys, given as a string or a list
+//	STARTS WITH, ENDS WITH               string prefixes and suffixes
+//	CONTAINS TEXT                        substrings
+//	ISTARTS WITH, IENDS WITH             case-insensitive prefixes and suffixes
+//	ICONTAINS TEXT                       case-insensitive substrings
+//
+// Fields are paths like childObject.name or are enclosed in backquotes, which they must
+// not contain. Values are
+// double or single quoted strings, integers, floats, true, false, null, timestamps like
+// 2024-01-01T10:00:00Z, dates like 2024-01-01 (which compare time fields by day), relative
+// times like now-1d/d and lists like [1, 2] or (1, 2).
+// Errors are returned as *SyntaxError.
+func ParseExpression(expression string) (filter.Condition, error) {
+	tokens, err := lexExpression(expression)
+	if err != nil {
+		return nil, err
+	}
+	p := &expressionParser{input: expression, tokens: tokens}
+	if p.peek().kind == eofToken {
+		return filter.Where(nil), nil
+	}
+	condition, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != eofToken {
+		return nil, p.errorf(t, "unexpected %s", t.describe())
+	}
+	return filter.Where(condition), nil
+}
+
+type tokenKind int
+
+const (
+	eofToken tokenKind = iota
+	identToken
+	fieldToken
+	stringToken
+	numberToken
+	timeToken
+	symbolToken
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	value any
+	pos   int
+}
+
+func (t token) describe() string {
+	if t.kind == eofToken {
+		return "end of input"
+	}
+	return fmt.Sprintf("'%s'", t.text)
+}
+
+func (t token) is(kind tokenKind, text string) bool {
+	return t.kind == kind && strings.EqualFold(t.text, text)
+}
+
+var (
+	timestampPattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}(T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2}))?`)
+	numberPattern    = regexp.MustCompile(`^-?\d+(\.\d+)?([eE][+-]?\d+)?`)
+	segmentPattern   = regexp.MustCompile(`^\[(-?\d+|\*|"(\\.|[^"\\])*")\]`)
+	relativeAliases  = map[string]bool{"now": true, "today": true, "yesterday": true, "tomorrow": true}
+)
+
+func isIdentStart(r rune) bool {
+	return r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
+
+func isIdentPart(r rune) bool {
+	return isIdentStart(r) || (r >= '0' && r <= '9')
+}
+
+func lexExpression(input string) ([]token, error) {
+	var tokens []token
+	pos := 0
+	for pos < len(input) {
+		r, size := utf8.DecodeRuneInString(input[pos:])
+		if r == ' ' || r == '\t' || r == '\n' || r == '\r' {
+			pos += size
+			continue
+		}
+		start := pos
+		rest := input[pos:]
+		switch {
+		case r == '"' || r == '\'':
+			s, n, err := lexString(rest)
+			if err != nil {
+				return nil, newSyntaxError(input, start, err.Error())
+			}
+			tokens = append(tokens, token{kind: stringToken, text: rest[:n], value: s, pos: start})
+			pos += n
+		case r == '`':
+			end := strings.IndexByte(rest[1:], '`')
+			if end < 0 {
+				return nil, newSyntaxError(input, start, "unterminated field name")
+			}
+			tokens = append(tokens, token{kind: fieldToken, text: rest[:end+2], value: rest[1 : end+1], pos: start})
+			pos += end + 2
+		case timestampPattern.MatchString(rest):
+			text := timestampPattern.FindString(rest)
+			var value any = text
+			if len(text) > len(dateLayout) {
+				t, err := time.Parse(time.RFC3339Nano, text)
+				if err != nil {
+					return nil, newSyntaxError(input, start, fmt.Sprintf("invalid timestamp '%s'", text))
+				}
+				value = t
+			} else if _, err := time.Parse(dateLayout, text); err != nil {
+				return nil, newSyntaxError(input, start, fmt.Sprintf("invalid date '%s'", text))
+			}
+			tokens = append(tokens, token{kind: timeToken, text: text, value: value, pos: start})
+			pos += len(text)
+		case numberPattern.MatchString(rest):
+			text := numberPattern.FindString(rest)
+			value, err := parseNumber(text)
+			if err != nil {
+				return nil, newSyntaxError(input, start, err.Error())
+			}
+			tokens = append(tokens, token{kind: numberToken, text: text, value: value, pos: start})
+			pos += len(text)
+		case isIdentStart(r):
+			n := lexIdent(rest)
+			tokens = append(tokens, token{kind: identToken, text: rest[:n], pos: start})
+			pos += n
+		default:
+			symbol := ""
+			for _, s := range []string{"==", "!=", "<>", ">=", "<=", "=", ">", "<", "(", ")", "[", "]", ","} {
+				if strings.HasPrefix(rest, s) {
+					symbol = s
+					break
+				}
+			}
+			if symbol == "" {
+				return nil, newSyntaxError(input, start, fmt.Sprintf("unexpected character '%c'", r))
+			}
+			tokens = append(tokens, token{kind: symbolToken, text: symbol, pos: start})
+			pos += len(symbol)
+		}
+	}
+	return append(tokens, token{kind: eofToken, pos: len(input)}), nil
+}
+
+// lexIdent returns the length of the identifier at the start of the input. Identifiers
+// are field paths with segments separated by dots and index segments like [0], [*] or
+// ["key"], or relative times like now-1d/d.
+func lexIdent(input string) int {
+	n := 0
+	for n < len(input) && isIdentPart(rune(input[n])) {
+		n++
+	}
+	if relativeAliases[strings.ToLower(input[:n])] {
+		for n < len(input) && (isIdentPart(rune(input[n])) || strings.IndexByte("+-/", input[n]) >= 0) {
+			n++
+		}
+		return n
+	}
+	keyword := expressionKeywords[strings.ToUpper(input[:n])]
+	for n < len(input) {
+		switch {
+		case input[n] == '.' && n+1 < len(input) && isIdentStart(rune(input[n+1])):
+			n++
+			for n < len(input) && isIdentPart(rune(input[n])) {
+				n++
+			}
+		case input[n] == '[' && !keyword && segmentPattern.MatchString(input[n:]):
+			n += len(segmentPattern.FindString(input[n:]))
+		default:
+			return n
+		}
+	}
+	return n
+}
+
+// lexString returns the value and the length of the quoted string at the start of the input.
+func lexString(input string) (string, int, error) {
+	quote := input[0]
+	var sb strings.Builder
+	for i := 1; i < len(input); i++ {
+		c := input[i]
+		switch {
+		case c == quote:
+			if quote == '"' {
+				s, err := strconv.Unquote(input[:i+1])
+				if err != nil {
+					return "", 0, fmt.Errorf("invalid string %s", input[:i+1])
+				}
+				return s, i + 1, nil
+			}
+			return sb.String(), i + 1, nil
+		case c == '\\' && i+1 < len(input):
+			i++
+			sb.WriteByte(input[i])
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string")
+}
+
+func parseNumber(text string) (any, error) {
+	if strings.ContainsAny(text, ".eE") {
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number '%s'", text)
+		}
+		return f, nil
+	}
+	i, err := strconv.ParseInt(text, 10, 64)
+	if err != nil || i < math.MinInt || i > math.MaxInt {
+		return nil, fmt.Errorf("invalid number '%s'", text)
+	}
+	return int(i), nil
+}
+
+func newSyntaxError(input string, offset int, message string) *SyntaxError {
+	line := 1 + strings.Count(input[:offset], "\n")
+	column := 1 + utf8.RuneCountInString(input[strings.LastIndexByte(input[:offset], '\n')+1:offset])
+	return &SyntaxError{Offset: offset, Line: line, Column: column, Message: message}
+}
+
+type expressionParser struct {
+	input  string
+	tokens []token
+	pos    int
+}
+
+func (p *expressionParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *expressionParser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != eofToken {
+		p.pos++
+	}
+	return t
+}
+
+func (p *expressionParser) errorf(t token, format string, args ...any) error {
+	return newSyntaxError(p.input, t.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *expressionParser) parseOr() (filter.Condition, error) {
+	return p.parseJunction("OR", p.parseAnd, filter.Or)
+}
+
+func (p *expressionParser) parseAnd() (filter.Condition, error) {
+	return p.parseJunction("AND", p.parseUnary, filter.And)
+}
+
+func (p *expressionParser) parseJunction(keyword string, parse func() (filter.Condition, error), junction func(...filter.Condition) filter.Condition) (filter.Condition, error) {
+	condition, err := parse()
+	if err != nil {
+		return nil, err
+	}
+	conditions := []filter.Condition{condition}
+	for p.peek().is(identToken, keyword) {
+		p.next()
+		condition, err := parse()
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, condition)
+	}
+	if len(conditions) == 1 {
+		return conditions[0], nil
+	}
+	return junction(conditions...), nil
+}
+
+func (p *expressionParser) parseUnary() (filter.Condition, error) {
+	t := p.peek()
+	switch {
+	case t.is(identToken, "NOT"):
+		p.next()
+		condition, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return filter.Not(condition), nil
+	case t.is(symbolToken, "("):
+		p.next()
+		condition, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if end := p.next(); !end.is(symbolToken, ")") {
+			return nil, p.errorf(end, "expected ')' but found %s", end.describe())
+		}
+		return filter.Group(condition), nil
+	}
+	return p.parsePredicate()
+}
+
+func (p *expressionParser) parsePredicate() (filter.Condition, error) {
+	t := p.next()
+	var field string
+	switch {
+	case t.kind == fieldToken:
+		field = t.value.(string)
+	case t.kind == identToken && !junctionKeywords[strings.ToUpper(t.text)]:
+		field = t.text
+	default:
+		return nil, p.errorf(t, "expected field but found %s", t.describe())
+	}
+
+	opToken := p.peek()
+	op, ok := p.parseOperator()
+	if !ok {
+		return nil, p.errorf(opToken, "expected operator but found %s", opToken.describe())
+	}
+	var value any
+	valueToken := p.peek()
+	if !op.noValue {
+		var err error
+		value, err = p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		if op.listValue {
+			if _, ok := value.([]any); !ok {
+				return nil, p.errorf(valueToken, "%s requires a list", op.keywords)
+			}
+		}
+	}
+	condition, err := op.condition(field, value)
+	if err != nil {
+		return nil, p.errorf(valueToken, "%s", err)
+	}
+	return condition, nil
+}
+
+// parseOperator consumes the operator with the most keywords matching the next tokens.
+func (p *expressionParser) parseOperator() (expressionOperator, bool) {
+	var match expressionOperator
+	length := 0
+	for _, op := range expressionOperators {
+		keywords := strings.Fields(op.keywords)
+		if len(keywords) <= length || p.pos+len(keywords) > len(p.tokens) {
+			continue
+		}
+		matches := true
+		for i, keyword := range keywords {
+			t := p.tokens[p.pos+i]
+			if (t.kind != identToken && t.kind != symbolToken) || !strings.EqualFold(t.text, keyword) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			match = op
+			length = len(keywords)
+		}
+	}
+	p.pos += length
+	return match, length > 0
+}
+
+func (p *expressionParser) parseValue() (any, error) {
+	t := p.next()
+	switch t.kind {
+	case stringToken, numberToken, timeToken:
+		return t.value, nil
+	case identToken:
+		switch strings.ToLower(t.text) {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		alias := t.text
+		if i := strings.IndexAny(alias, "+-/"); i >= 0 {
+			alias = alias[:i]
+		}
+		if relativeAliases[strings.ToLower(alias)] {
+			r, err := ParseRelativeTime(t.text)
+			if err != nil {
+				return nil, p.errorf(t, "%s", err)
+			}
+			return r, nil
+		}
+		return nil, p.errorf(t, "unexpected identifier %s, strings must be quoted", t.describe())
+	case symbolToken:
+		if t.text == "[" || t.text == "(" {
+			return p.parseList(t)
+		}
+	}
+	return nil, p.errorf(t, "expected value but found %s", t.describe())
+}
+
+func (p *expressionParser) parseList(start token) (any, error) {
+	end := "]"
+	if start.text == "(" {
+		end = ")"
+	}
+	values := []any{}
+	if p.peek().is(symbolToken, end) {
+		p.next()
+		return values, nil
+	}
+	for {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		t := p.next()
+		if t.is(symbolToken, end) {
+			return values, nil
+		}
+		if !t.is(symbolToken, ",") {
+			return nil, p.errorf(t, "expected ',' or '%s' but found %s", end, t.describe())
+		}
+	}
+}
+
+// FormatExpression formats the condition in the syntax of ParseExpression.
+// Parsing the result yields an equivalent condition: groups are added where the precedence
+// of AND over OR requires them, and integers and floats are parsed as int and float64.
+// Values which cannot be represented in the syntax result in an error.
+func FormatExpression(condition filter.Condition) (string, error) {
+	var sb strings.Builder
+	if err := formatExpression(&sb, condition, 0); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+const (
+	orPrecedence = iota + 1
+	andPrecedence
+	unaryPrecedence
+)
+
+func formatExpression(sb *strings.Builder, condition filter.Condition, precedence int) error {
+	if condition == nil || (reflect.ValueOf(condition).Kind() == reflect.Ptr && reflect.ValueOf(condition).IsNil()) {
+		if precedence == 0 {
+			return nil
+		}
+		return fmt.Errorf("condition must not be nil")
+	}
+	switch c := condition.(type) {
+	case *filter.WhereCondition:
+		return formatExpression(sb, c.Condition, precedence)
+	case *filter.GroupCondition:
+		if c.Condition == nil {
+			return fmt.Errorf("GROUP condition must have a condition")
+		}
+		sb.WriteString("(")
+		if err := formatExpression(sb, c.Condition, orPrecedence); err != nil {
+			return err
+		}
+		sb.WriteString(")")
+		return nil
+	case *filter.NotCondition:
+		if in, ok := c.Condition.(*filter.InCondition); ok {
+			return formatPredicate(sb, in.Field, "NOT IN", in.Value)
+		}
+		if c.Condition == nil {
+			return fmt.Errorf("NOT condition must have a condition")
+		}
+		sb.WriteString("NOT ")
+		return formatExpression(sb, c.Condition, unaryPrecedence)
+	case *filter.AndCondition:
+		return formatJunction(sb, c.Conditions, " AND ", andPrecedence, precedence)
+	case *filter.OrCondition:
+		return formatJunction(sb, c.Conditions, " OR ", orPrecedence, precedence)
+	case *filter.EqualsCondition:
+		return formatPredicate(sb, c.Field, "=", c.Value)
+	case *filter.NotEqualsCondition:
+		return formatPredicate(sb, c.Field, "!=", c.Value)
+	case *filter.GreaterThanCondition:
+		return formatPredicate(sb, c.Field, ">", c.Value)
+	case *filter.GreaterThanOrEqualCondition:
+		return formatPredicate(sb, c.Field, ">=", c.Value)
+	case *filter.LowerThanCondition:
+		return formatPredicate(sb, c.Field, "<", c.Value)
+	case *filter.LowerThanOrEqualCondition:
+		return formatPredicate(sb, c.Field, "<=", c.Value)
+	case *filter.InCondition:
+		return formatPredicate(sb, c.Field, "IN", c.Value)
+	case *filter.IsNilCondition:
+		return formatPredicate(sb, c.Field, "IS NULL")
+	case *filter.NotNilCondition:
+		return formatPredicate(sb, c.Field, "IS NOT NULL")
+	case *filter.ArrayContainsCondition:
+		return formatPredicate(sb, c.Field, "CONTAINS", c.Value)
+	case *filter.ArrayContainsArrayCondition:
+		return formatPredicate(sb, c.Field, "CONTAINS ARRAY", c.Value)
+	case *filter.ContainsCondition:
+		return formatPredicate(sb, c.Field, "ICONTAINS", c.Value)
+	case *filter.ArrayIsContainedCondition:
+		return formatPredicate(sb, c.Field, "CONTAINED IN", c.Value)
+	case *filter.ArraysOverlapCondition:
+		return formatPredicate(sb, c.Field, "OVERLAPS", c.Value)
+	case *filter.OverlapsCondition:
+		return formatPredicate(sb, c.Field, "OVERLAP", c.Value)
+	case *filter.RegexCondition:
+		return formatPredicate(sb, c.Field, "MATCHES", c.Expression)
+	case *filter.NotRegexCondition:
+		return formatPredicate(sb, c.Field, "NOT MATCHES", c.Expression)
+	case *LengthEqualsCondition:
+		return formatPredicate(sb, c.Field, "LENGTH =", c.Length)
+	case *LengthGreaterThanCondition:
+		return formatPredicate(sb, c.Field, "LENGTH >", c.Length)
+	case *LengthLowerThanCondition:
+		return formatPredicate(sb, c.Field, "LENGTH <", c.Length)
+	case *IsEmptyCondition:
+		return formatPredicate(sb, c.Field, "IS EMPTY")
+	case *NotEmptyCondition:
+		return formatPredicate(sb, c.Field, "IS NOT EMPTY")
+	case *HasKeyCondition:
+		return formatPredicate(sb, c.Field, "HAS KEY", c.Key)
+	case *HasAnyKeyCondition:
+		return formatPredicate(sb, c.Field, "HAS ANY KEY", c.Keys)
+	case *HasAllKeysCondition:
+		return formatPredicate(sb, c.Field, "HAS ALL KEYS", c.Keys)
+	case *StartsWithCondition:
+		return formatPredicate(sb, c.Field, ignoreCasePrefix(c.IgnoreCase)+"STARTS WITH", c.Value)
+	case *EndsWithCondition:
+		return formatPredicate(sb, c.Field, ignoreCasePrefix(c.IgnoreCase)+"ENDS WITH", c.Value)
+	case *ContainsTextCondition:
+		return formatPredicate(sb, c.Field, ignoreCasePrefix(c.IgnoreCase)+"CONTAINS TEXT", c.Value)
+	}
+	return fmt.Errorf("unknown condition: %s", condition.Type())
+}
+
+func formatJunction(sb *strings.Builder, conditions []filter.Condition, separator string, junctionPrecedence int, precedence int) error {
+	if len(conditions) < 2 {
+		return fmt.Errorf("%s condition must have at least two conditions", strings.TrimSpace(separator))
+	}
+	parenthesize := precedence > junctionPrecedence
+	if parenthesize {
+		sb.WriteString("(")
+	}
+	for i, c := range conditions {
+		if i > 0 {
+			sb.WriteString(separator)
+		}
+		if err := formatExpression(sb, c, junctionPrecedence+1); err != nil {
+			return err
+		}
+	}
+	if parenthesize {
+		sb.WriteString(")")
+	}
+	return nil
+}
+
+func formatPredicate(sb *strings.Builder, field string, operator string, value ...any) error {
+	f, err := formatField(field)
+	if err != nil {
+		return err
+	}
+	sb.WriteString(f)
+	sb.WriteString(" ")
+	sb.WriteString(operator)
+	for _, v := range value {
+		s, err := formatValue(reflect.ValueOf(v))
+		if err != nil {
+			return fmt.Errorf("cannot format value of field '%s': %w", field, err)
+		}
+		sb.WriteString(" ")
+		sb.WriteString(s)
+	}
+	return nil
+}
+
+func formatField(field string) (string, error) {
+	if field != "" && isIdentStart(rune(field[0])) && lexIdent(field) == len(field) &&
+		!junctionKeywords[strings.ToUpper(field)] {
+		return field, nil
+	}
+	if strings.Contains(field, "`") {
+		return "", fmt.Errorf("field '%s' cannot be formatted since it contains a backquote", field)
+	}
+	return "`" + field + "`", nil
+}
+
+func formatValue(v reflect.Value) (string, error) {
+	if !v.IsValid() {
+		return "null", nil
+	}
+	switch value := v.Interface().(type) {
+	case time.Time:
+		return value.Format(time.RFC3339Nano), nil
+	case time.Duration:
+		return strconv.Quote(value.String()), nil
+	case RelativeTime:
+		return value.String(), nil
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return "null", nil
+		}
+		return formatValue(v.Elem())
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.String:
+		return strconv.Quote(v.String()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v.Uint() > math.MaxInt64 {
+			return "", fmt.Errorf("number %d is too large", v.Uint())
+		}
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return "", fmt.Errorf("number %v cannot be represented", f)
+		}
+		s := strconv.FormatFloat(f, 'g', -1, 64)
+		if !strings.ContainsAny(s, ".e") {
+			s += ".0"
+		}
+		return s, nil
+	case reflect.Slice, reflect.Array:
+		values := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			s, err := formatValue(v.Index(i))
+			if err != nil {
+				return "", err
+			}
+			values = append(values, s)
+		}
+		return "[" + strings.Join(values, ", ") + "]", nil
+	}
+	return "", fmt.Errorf("values of type %s cannot be formatted", v.Type())
+}
@@ -0,0 +1,284 @@
+package filterobject
+
+import (
+	"github.com/stretchr/testify/require"
+	"github.com/xafelium/filter"
+	"testing"
+	"time"
+)
+
+func TestParseExpression(t *testing.T) {
+	tests := []struct {
+		expression string
+		expected   filter.Condition
+	}{
+		{
+			expression: "  ",
+			expected:   filter.Where(nil),
+		},
+		{
+			expression: `taskType = "a" AND (createdAt > 2024-01-01 OR nicknames CONTAINS "x")`,
+			expected: filter.Where(filter.And(
+				filter.Equals("taskType", "a"),
+				filter.Group(filter.Or(
+					filter.GreaterThan("createdAt", "2024-01-01"),
+					filter.ArrayContains("nicknames", "x"),
+				)),
+			)),
+		},
+		{
+			expression: `a = 1 OR b = 2 AND c = 3 OR NOT d = 4`,
+			expected: filter.Where(filter.Or(
+				filter.Equals("a", 1),
+				filter.And(filter.Equals("b", 2), filter.Equals("c", 3)),
+				filter.Not(filter.Equals("d", 4)),
+			)),
+		},
+		{
+			expression: `id == -1 and id != 2.5 and id <> 1e3 and id > 0 and id >= 0 and id < 10 and id <= 10`,
+			expected: filter.Where(filter.And(
+				filter.Equals("id", -1),
+				filter.NotEquals("id", 2.5),
+				filter.NotEquals("id", 1000.0),
+				filter.GreaterThan("id", 0),
+				filter.GreaterThanOrEqual("id", 0),
+				filter.LowerThan("id", 10),
+				filter.LowerThanOrEqual("id", 10),
+			)),
+		},
+		{
+			expression: `id IN (1, 2) AND id not in [] AND childObject IS NULL AND name is not null`,
+			expected: filter.Where(filter.And(
+				filter.In("id", []any{1, 2}),
+				filter.Not(filter.In("id", []any{})),
+				filter.IsNil("childObject"),
+				filter.NotNil("name"),
+			)),
+		},
+		{
+			expression: `tags CONTAINS ARRAY ["a"] AND name ICONTAINS 'o\'b' AND tags CONTAINED IN ["a", "b"] AND tags OVERLAPS ["c"] AND period OVERLAP [1, 2]`,
+			expected: filter.Where(filter.And(
+				filter.ArrayContainsArray("tags", []any{"a"}),
+				filter.Contains("name", "o'b"),
+				filter.ArrayIsContained("tags", []any{"a", "b"}),
+				filter.ArraysOverlap("tags", []any{"c"}),
+				filter.Overlaps("period", []any{1, 2}),
+			)),
+		},
+		{
+			expression: `name MATCHES "^a\\d" AND name NOT MATCHES "b$"`,
+			expected: filter.Where(filter.And(
+				filter.Regex("name", `^a\d`),
+				filter.NotRegex("name", "b$"),
+			)),
+		},
+		{
+			expression: "childObject.name = true AND `task type` = false AND items[0].sku = null AND labels[\"team-a\"] = \"x\" AND addresses[*].country = \"de\"",
+			expected: filter.Where(filter.And(
+				filter.Equals("childObject.name", true),
+				filter.Equals("task type", false),
+				filter.Equals("items[0].sku", nil),
+				filter.Equals(`labels["team-a"]`, "x"),
+				filter.Equals("addresses[*].country", "de"),
+			)),
+		},
+		{
+			expression: `createdAt >= 2024-01-02T03:04:05.5+01:00 AND createdAt < now-1d/d AND deletedAt > today`,
+			expected: filter.Where(filter.And(
+				filter.GreaterThanOrEqual("createdAt", time.Date(2024, 1, 2, 3, 4, 5, 500000000, time.FixedZone("", 3600))),
+				filter.LowerThan("createdAt", RelativeTime{Days: -1, StartOf: Day}),
+				filter.GreaterThan("deletedAt", RelativeTime{StartOf: Day}),
+			)),
+		},
+		{
+			expression: `nicknames LENGTH = 0 AND houseIds length > 2 AND name LENGTH < 50 AND tags IS EMPTY AND labels is not empty`,
+			expected: filter.Where(filter.And(
+				LengthEquals("nicknames", 0),
+				LengthGreaterThan("houseIds", 2),
+				LengthLowerThan("name", 50),
+				IsEmpty("tags"),
+				NotEmpty("labels"),
+			)),
+		},
+		{
+			expression: `labels HAS KEY "env" AND labels has any key ["a", "b"] AND labels HAS ALL KEYS [] AND labels["team-a"] = "x"`,
+			expected: filter.Where(filter.And(
+				HasKey("labels", "env"),
+				HasAnyKey("labels", "a", "b"),
+				HasAllKeys("labels"),
+				filter.Equals(`labels["team-a"]`, "x"),
+			)),
+		},
+		{
+			expression: `name STARTS WITH "a" AND name istarts with "b" AND name ENDS WITH "c" AND name IENDS WITH "d" AND tags CONTAINS TEXT "e" AND tags ICONTAINS TEXT "f" AND tags CONTAINS "g"`,
+			expected: filter.Where(filter.And(
+				StartsWith("name", "a"),
+				StartsWithIgnoreCase("name", "b"),
+				EndsWith("name", "c"),
+				EndsWithIgnoreCase("name", "d"),
+				ContainsText("tags", "e"),
+				ContainsTextIgnoreCase("tags", "f"),
+				filter.ArrayContains("tags", "g"),
+			)),
+		},
+		{
+			expression: "NOT (a = 1)\n\tOR NOT NOT b = 2",
+			expected: filter.Where(filter.Or(
+				filter.Not(filter.Group(filter.Equals("a", 1))),
+				filter.Not(filter.Not(filter.Equals("b", 2))),
+			)),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			condition, err := ParseExpression(test.expression)
+			require.NoError(t, err)
+			require.Equal(t, test.expected.String(), condition.String())
+			require.Equal(t, test.expected, condition)
+		})
+	}
+}
+
+func TestParseExpressionErrors(t *testing.T) {
+	tests := []struct {
+		expression string
+		err        string
+	}{
+		{expression: `a = `, err: "syntax error at line 1, column 5: expected value but found end of input"},
+		{expression: `a = b`, err: "syntax error at line 1, column 5: unexpected identifier 'b', strings must be quoted"},
+		{expression: `a b`, err: "syntax error at line 1, column 3: expected operator but found 'b'"},
+		{expression: `= 1`, err: "syntax error at line 1, column 1: expected field but found '='"},
+		{expression: `a = 1 b = 2`, err: "syntax error at line 1, column 7: unexpected 'b'"},
+		{expression: "a = 1 AND\n  (b = 2", err: "syntax error at line 2, column 9: expected ')' but found end of input"},
+		{expression: `a = "x`, err: "syntax error at line 1, column 5: unterminated string"},
+		{expression: `a = 1 & b = 2`, err: "syntax error at line 1, column 7: unexpected character '&'"},
+		{expression: `a IN 1`, err: "syntax error at line 1, column 6: IN requires a list"},
+		{expression: `a IN [1 2]`, err: "syntax error at line 1, column 9: expected ',' or ']' but found '2'"},
+		{expression: `a MATCHES "("`, err: "syntax error at line 1, column 11: invalid regular expression: error parsing regexp: missing closing ): `(`"},
+		{expression: `a ICONTAINS 1`, err: "syntax error at line 1, column 13: ICONTAINS requires a string"},
+		{expression: `a > now-1x`, err: "syntax error at line 1, column 5: invalid relative time \"now-1x\": unknown unit 'x'"},
+		{expression: `a > 2024-13-01`, err: "syntax error at line 1, column 5: invalid date '2024-13-01'"},
+		{expression: `a > 99999999999999999999`, err: "syntax error at line 1, column 5: invalid number '99999999999999999999'"},
+		{expression: `a LENGTH > -1`, err: "syntax error at line 1, column 12: LENGTH requires a non-negative integer"},
+		{expression: `a LENGTH = 1.0`, err: "syntax error at line 1, column 12: LENGTH requires a non-negative integer"},
+		{expression: `a HAS KEY 1`, err: "syntax error at line 1, column 11: HAS KEY requires a string"},
+		{expression: `a HAS ALL KEYS ["a", 1]`, err: "syntax error at line 1, column 16: HAS ALL KEYS requires a list of strings"},
+		{expression: `a STARTS WITH 1`, err: "syntax error at line 1, column 15: STARTS WITH requires a string"},
+		{expression: "`a = 1", err: "syntax error at line 1, column 1: unterminated field name"},
+		{expression: `AND = 1`, err: "syntax error at line 1, column 1: expected field but found 'AND'"},
+	}
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			_, err := ParseExpression(test.expression)
+			var syntaxErr *SyntaxError
+			require.ErrorAs(t, err, &syntaxErr)
+			require.EqualError(t, err, test.err)
+		})
+	}
+}
+
+func TestFormatExpression(t *testing.T) {
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		condition filter.Condition
+		expected  string
+	}{
+		{condition: filter.Where(nil), expected: ""},
+		{
+			condition: filter.Where(filter.And(
+				filter.Equals("taskType", "a\"b"),
+				filter.Or(filter.GreaterThan("createdAt", createdAt), filter.ArrayContains("nicknames", "x")),
+			)),
+			expected: `taskType = "a\"b" AND (createdAt > 2024-01-02T03:04:05Z OR nicknames CONTAINS "x")`,
+		},
+		{
+			condition: filter.Or(filter.And(filter.Equals("a", 1), filter.Equals("b", 2.0)), filter.Not(filter.Or(filter.IsNil("c"), filter.NotNil("d")))),
+			expected:  `a = 1 AND b = 2.0 OR NOT (c IS NULL OR d IS NOT NULL)`,
+		},
+		{
+			condition: filter.And(filter.In("id", []int{1, 2}), filter.Not(filter.In("id", []string{"x"})), filter.NotEquals("and", nil)),
+			expected:  "id IN [1, 2] AND id NOT IN [\"x\"] AND `and` != null",
+		},
+		{
+			condition: filter.And(
+				filter.ArrayContainsArray("tags", []string{"a"}),
+				filter.Contains("name", "o"),
+				filter.ArrayIsContained("tags", []string{"a"}),
+				filter.ArraysOverlap("tags", []string{"b"}),
+				filter.Overlaps("period", []uint8{1}),
+			),
+			expected: `tags CONTAINS ARRAY ["a"] AND name ICONTAINS "o" AND tags CONTAINED IN ["a"] AND tags OVERLAPS ["b"] AND period OVERLAP [1]`,
+		},
+		{
+			condition: filter.Group(filter.And(
+				filter.Regex("name", `^\d`),
+				filter.NotRegex("name", "x"),
+				filter.LowerThan("timeout", 5*time.Minute),
+				filter.LowerThanOrEqual("createdAt", RelativeTime{Days: -1}),
+				filter.GreaterThanOrEqual("task type", 1.5e100),
+			)),
+			expected: "(name MATCHES \"^\\\\d\" AND name NOT MATCHES \"x\" AND timeout < \"5m0s\" AND createdAt <= now-1d AND `task type` >= 1.5e+100)",
+		},
+		{
+			condition: filter.Or(LengthEquals("nicknames", 0), LengthGreaterThan("houseIds", 2), LengthLowerThan("name", 50), IsEmpty("tags"), NotEmpty("labels")),
+			expected:  `nicknames LENGTH = 0 OR houseIds LENGTH > 2 OR name LENGTH < 50 OR tags IS EMPTY OR labels IS NOT EMPTY`,
+		},
+		{
+			condition: filter.And(HasKey("labels", "team-a"), HasAnyKey("labels", "a"), HasAllKeys("labels", "a", "b")),
+			expected:  `labels HAS KEY "team-a" AND labels HAS ANY KEY ["a"] AND labels HAS ALL KEYS ["a", "b"]`,
+		},
+		{
+			condition: filter.Or(StartsWith("name", "a"), StartsWithIgnoreCase("name", "b"), EndsWith("name", "c"), EndsWithIgnoreCase("name", "d"), ContainsText("tags", "e"), ContainsTextIgnoreCase("tags", "f")),
+			expected:  `name STARTS WITH "a" OR name ISTARTS WITH "b" OR name ENDS WITH "c" OR name IENDS WITH "d" OR tags CONTAINS TEXT "e" OR tags ICONTAINS TEXT "f"`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			expression, err := FormatExpression(test.condition)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, expression)
+
+			parsed, err := ParseExpression(expression)
+			require.NoError(t, err)
+			formatted, err := FormatExpression(parsed)
+			require.NoError(t, err)
+			require.Equal(t, expression, formatted)
+		})
+	}
+
+	_, err := FormatExpression(filter.Equals("a", struct{}{}))
+	require.EqualError(t, err, "cannot format value of field 'a': values of type struct {} cannot be formatted")
+
+	_, err = FormatExpression(filter.And(filter.Equals("a", 1)))
+	require.EqualError(t, err, "AND condition must have at least two conditions")
+
+	_, err = FormatExpression(&unknownCondition{})
+	require.EqualError(t, err, "unknown condition: UnknownCondition")
+
+	_, err = FormatExpression(filter.Not(filter.Equals("a`b", 1)))
+	require.EqualError(t, err, "field 'a`b' cannot be formatted since it contains a backquote")
+}
+
+func TestParseExpressionEvaluation(t *testing.T) {
+	obj := TestObject{
+		Id:          1,
+		TaskType:    "magic",
+		Name:        "Alice",
+		Nicknames:   []string{"ali"},
+		CreatedAt:   time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		ChildObject: &TestObject{Name: "bob"},
+	}
+	for expression, expected := range map[string]bool{
+		`taskType = "magic" AND createdAt = 2024-01-01`:        true,
+		`createdAt > 2024-01-01`:                               false,
+		`nicknames CONTAINS "ali" AND name ICONTAINS "LIC"`:    true,
+		`childObject.name MATCHES "^b" OR id IN [2, 3]`:        true,
+		`id NOT IN [1] OR childObject.childObject IS NOT NULL`: false,
+	} {
+		condition, err := ParseExpression(expression)
+		require.NoError(t, err)
+		applies, err := FilterApplies(obj, condition)
+		require.NoError(t, err, expression)
+		require.Equal(t, expected, applies, expression)
+	}
+}
@@ -0,0 +1,141 @@
+package filterobject
+
+import (
+	"fmt"
+	"github.com/xafelium/filter"
+	"reflect"
+)
+
+const (
+	HasKeyConditionType     = "HasKeyCondition"
+	HasAnyKeyConditionType  = "HasAnyKeyCondition"
+	HasAllKeysConditionType = "HasAllKeysCondition"
+)
+
+func init() {
+	conditionEvaluators[HasKeyConditionType] = applyHasKey
+	conditionEvaluators[HasAnyKeyConditionType] = applyHasAnyKey
+	conditionEvaluators[HasAllKeysConditionType] = applyHasAllKeys
+}
+
+// HasKeyCondition filters maps with an entry with the given key. Keys are case-sensitive.
+type HasKeyCondition struct {
+	Field string
+	Key   string
+}
+
+// HasKey creates a new HasKeyCondition.
+func HasKey(field string, key string) filter.Condition {
+	return &HasKeyCondition{
+		Field: field,
+		Key:   key,
+	}
+}
+
+// String returns the string representation of the condition.
+func (c *HasKeyCondition) String() string {
+	return fmt.Sprintf("%s HAS KEY %q", c.Field, c.Key)
+}
+
+// Type returns the name of the condition.
+func (c *HasKeyCondition) Type() string {
+	return HasKeyConditionType
+}
+
+// HasAnyKeyCondition filters maps with an entry with at least one of the given keys.
+type HasAnyKeyCondition struct {
+	Field string
+	Keys  []string
+}
+
+// HasAnyKey creates a new HasAnyKeyCondition.
+func HasAnyKey(field string, keys ...string) filter.Condition {
+	return &HasAnyKeyCondition{
+		Field: field,
+		Keys:  keys,
+	}
+}
+
+// String returns the string representation of the condition.
+func (c *HasAnyKeyCondition) String() string {
+	return fmt.Sprintf("%s HAS ANY KEY %q", c.Field, c.Keys)
+}
+
+// Type returns the name of the condition.
+func (c *HasAnyKeyCondition) Type() string {
+	return HasAnyKeyConditionType
+}
+
+// HasAllKeysCondition filters maps with entries with all the given keys.
+type HasAllKeysCondition struct {
+	Field string
+	Keys  []string
+}
+
+// HasAllKeys creates a new HasAllKeysCondition.
+func HasAllKeys(field string, keys ...string) filter.Condition {
+	return &HasAllKeysCondition{
+		Field: field,
+		Keys:  keys,
+	}
+}
+
+// String returns the string representation of the condition.
+func (c *HasAllKeysCondition) String() string {
+	return fmt.Sprintf("%s HAS ALL KEYS %q", c.Field, c.Keys)
+}
+
+// Type returns the name of the condition.
+func (c *HasAllKeysCondition) Type() string {
+	return HasAllKeysConditionType
+}
+
+func applyHasKey(obj any, condition filter.Condition) (bool, error) {
+	c, ok := condition.(*HasKeyCondition)
+	if !ok {
+		return false, fmt.Errorf("condition is no HasKeyCondition")
+	}
+	return countKeys(obj, c.Field, []string{c.Key}, 1)
+}
+
+func applyHasAnyKey(obj any, condition filter.Condition) (bool, error) {
+	c, ok := condition.(*HasAnyKeyCondition)
+	if !ok {
+		return false, fmt.Errorf("condition is no HasAnyKeyCondition")
+	}
+	return countKeys(obj, c.Field, c.Keys, 1)
+}
+
+func applyHasAllKeys(obj any, condition filter.Condition) (bool, error) {
+	c, ok := condition.(*HasAllKeysCondition)
+	if !ok {
+		return false, fmt.Errorf("condition is no HasAllKeysCondition")
+	}
+	return countKeys(obj, c.Field, c.Keys, len(c.Keys))
+}
+
+// countKeys reports whether the map field of the object has entries for at least the given
+// number of the keys. Nil maps have no entries.
+func countKeys(obj any, name string, keys []string, count int) (bool, error) {
+	field, err := getField(obj, name)
+	if err != nil {
+		return false, err
+	}
+	field = indirect(field)
+	if !field.IsValid() {
+		return count == 0, nil
+	}
+	if !isStringMap(field.Type()) {
+		return false, fmt.Errorf("field must be a map with string keys but is of type %s", field.Type())
+	}
+	found := 0
+	for _, key := range keys {
+		if found >= count {
+			break
+		}
+		if field.MapIndex(reflect.ValueOf(key).Convert(field.Type().Key())).IsValid() {
+			found++
+		}
+	}
+	return found >= count, nil
+}
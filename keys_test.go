@@ -0,0 +1,100 @@
+package filterobject
+
+import (
+	"github.com/stretchr/testify/require"
+	"github.com/xafelium/filter"
+	"testing"
+)
+
+type AttributeName string
+
+type KeysTestObject struct {
+	Labels     map[string]string
+	Attributes map[AttributeName]any
+	Owner      *KeysTestObject
+	Name       string
+}
+
+func TestApplyKeyConditions(t *testing.T) {
+	obj := KeysTestObject{
+		Labels:     map[string]string{"env": "prod", "team-a": "x", "Tier": "1"},
+		Attributes: map[AttributeName]any{"size": 3},
+	}
+	for condition, expected := range map[filter.Condition]bool{
+		HasKey("labels", "env"):                   true,
+		HasKey("labels", "tier"):                  false,
+		HasKey("attributes", "size"):              true,
+		HasAnyKey("labels", "a", "team-a"):        true,
+		HasAnyKey("labels", "a", "b"):             false,
+		HasAnyKey("labels"):                       false,
+		HasAllKeys("labels", "env", "Tier"):       true,
+		HasAllKeys("labels", "env", "team-b"):     false,
+		HasAllKeys("labels"):                      true,
+		HasKey("owner.labels", "env"):             false,
+		filter.Not(HasKey("owner.labels", "env")): true,
+		filter.Equals(`labels["team-a"]`, "x"):    true,
+		filter.Equals(`attributes["size"]`, 3):    true,
+		filter.IsNil(`owner.labels["team-a"]`):    true,
+		filter.IsNil(`labels["team-b"]`):          true,
+		filter.Equals(`labels["env"]`, "prod"):    true,
+		filter.Equals(`owner["name"]`, "x"):       false,
+		filter.IsNil(`labels["tier"]`):            true,
+		filter.Equals("labels.tier", "1"):         true,
+		filter.IsNil(`labels["teamA"]`):           true,
+		filter.Equals("labels.teamA", "x"):        true,
+	} {
+		applies, err := FilterApplies(obj, condition)
+		require.NoError(t, err, condition.String())
+		require.Equal(t, expected, applies, condition.String())
+	}
+
+	applies, err := FilterApplies(map[string]any{"meta": map[string]any{"labels": map[string]any{"app.kubernetes.io/name": "api"}}},
+		filter.And(HasKey("meta.labels", "app.kubernetes.io/name"), filter.Equals(`meta.labels["app.kubernetes.io/name"]`, "api")))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	_, err = FilterApplies(obj, HasKey("name", "x"))
+	require.EqualError(t, err, "field must be a map with string keys but is of type string")
+}
+
+func TestGetFieldMap(t *testing.T) {
+	obj := map[string]any{
+		"id":        float64(1),
+		"task_type": "magic",
+		"nicknames": []any{"a", "b"},
+		"labels":    map[string]string{"env": "prod"},
+		"child":     nil,
+	}
+	tests := []struct {
+		condition filter.Condition
+		applies   bool
+	}{
+		{condition: filter.Equals("id", 1), applies: true},
+		{condition: filter.Equals("taskType", "magic"), applies: true},
+		{condition: filter.ArrayContains("nicknames", "b"), applies: true},
+		{condition: filter.Equals("labels.env", "prod"), applies: true},
+		{condition: filter.IsNil("labels.team"), applies: true},
+		{condition: filter.Equals("labels.team", ""), applies: false},
+		{condition: filter.IsNil("missing"), applies: true},
+		{condition: filter.IsNil("child.name"), applies: true},
+		{condition: filter.GreaterThan("missing", 1), applies: false},
+		{condition: filter.ArrayContains("missing", "a"), applies: false},
+		{condition: filter.ArraysOverlap("missing", []string{"a"}), applies: false},
+	}
+	for _, test := range tests {
+		applies, err := FilterApplies(obj, test.condition)
+		require.NoError(t, err, test.condition.String())
+		require.Equal(t, test.applies, applies, test.condition.String())
+	}
+
+	applies, err := FilterApplies(&TestMapObject{Labels: map[string]string{"env": "prod"}}, filter.Equals("labels.env", "prod"))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	_, err = FilterApplies(map[int]any{}, filter.Equals("id", 1))
+	require.EqualError(t, err, "invalid object type: map")
+}
+
+type TestMapObject struct {
+	Labels map[string]string
+}
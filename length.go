@@ -0,0 +1,206 @@
+package filterobject
+
+import (
+	"fmt"
+	"github.com/xafelium/filter"
+	"reflect"
+	"unicode/utf8"
+)
+
+const (
+	LengthEqualsConditionType      = "LengthEqualsCondition"
+	LengthGreaterThanConditionType = "LengthGreaterThanCondition"
+	LengthLowerThanConditionType   = "LengthLowerThanCondition"
+	IsEmptyConditionType           = "IsEmptyCondition"
+	NotEmptyConditionType          = "NotEmptyCondition"
+)
+
+func init() {
+	conditionEvaluators[LengthEqualsConditionType] = applyLengthEquals
+	conditionEvaluators[LengthGreaterThanConditionType] = applyLengthGreaterThan
+	conditionEvaluators[LengthLowerThanConditionType] = applyLengthLowerThan
+	conditionEvaluators[IsEmptyConditionType] = applyIsEmpty
+	conditionEvaluators[NotEmptyConditionType] = applyNotEmpty
+}
+
+// LengthEqualsCondition filters slices, arrays, maps and strings with the given length.
+// The length of strings is the number of their characters. Nil values have a length of 0.
+type LengthEqualsCondition struct {
+	Field  string
+	Length int
+}
+
+// LengthEquals creates a new LengthEqualsCondition.
+func LengthEquals(field string, length int) filter.Condition {
+	return &LengthEqualsCondition{
+		Field:  field,
+		Length: length,
+	}
+}
+
+// String returns the string representation of the condition.
+func (c *LengthEqualsCondition) String() string {
+	return fmt.Sprintf("len(%s) = %d", c.Field, c.Length)
+}
+
+// Type returns the name of the condition.
+func (c *LengthEqualsCondition) Type() string {
+	return LengthEqualsConditionType
+}
+
+// LengthGreaterThanCondition filters slices, arrays, maps and strings which are longer than the given length.
+type LengthGreaterThanCondition struct {
+	Field  string
+	Length int
+}
+
+// LengthGreaterThan creates a new LengthGreaterThanCondition.
+func LengthGreaterThan(field string, length int) filter.Condition {
+	return &LengthGreaterThanCondition{
+		Field:  field,
+		Length: length,
+	}
+}
+
+// String returns the string representation of the condition.
+func (c *LengthGreaterThanCondition) String() string {
+	return fmt.Sprintf("len(%s) > %d", c.Field, c.Length)
+}
+
+// Type returns the name of the condition.
+func (c *LengthGreaterThanCondition) Type() string {
+	return LengthGreaterThanConditionType
+}
+
+// LengthLowerThanCondition filters slices, arrays, maps and strings which are shorter than the given length.
+type LengthLowerThanCondition struct {
+	Field  string
+	Length int
+}
+
+// LengthLowerThan creates a new LengthLowerThanCondition.
+func LengthLowerThan(field string, length int) filter.Condition {
+	return &LengthLowerThanCondition{
+		Field:  field,
+		Length: length,
+	}
+}
+
+// String returns the string representation of the condition.
+func (c *LengthLowerThanCondition) String() string {
+	return fmt.Sprintf("len(%s) < %d", c.Field, c.Length)
+}
+
+// Type returns the name of the condition.
+func (c *LengthLowerThanCondition) Type() string {
+	return LengthLowerThanConditionType
+}
+
+// IsEmptyCondition filters nil values and empty slices, arrays, maps and strings.
+type IsEmptyCondition struct {
+	Field string
+}
+
+// IsEmpty creates a new IsEmptyCondition.
+func IsEmpty(field string) filter.Condition {
+	return &IsEmptyCondition{
+		Field: field,
+	}
+}
+
+// String returns the string representation of the condition.
+func (c *IsEmptyCondition) String() string {
+	return fmt.Sprintf("%s IS EMPTY", c.Field)
+}
+
+// Type returns the name of the condition.
+func (c *IsEmptyCondition) Type() string {
+	return IsEmptyConditionType
+}
+
+// NotEmptyCondition filters slices, arrays, maps and strings with at least one element or character.
+type NotEmptyCondition struct {
+	Field string
+}
+
+// NotEmpty creates a new NotEmptyCondition.
+func NotEmpty(field string) filter.Condition {
+	return &NotEmptyCondition{
+		Field: field,
+	}
+}
+
+// String returns the string representation of the condition.
+func (c *NotEmptyCondition) String() string {
+	return fmt.Sprintf("%s IS NOT EMPTY", c.Field)
+}
+
+// Type returns the name of the condition.
+func (c *NotEmptyCondition) Type() string {
+	return NotEmptyConditionType
+}
+
+func applyLengthEquals(obj any, condition filter.Condition) (bool, error) {
+	c, ok := condition.(*LengthEqualsCondition)
+	if !ok {
+		return false, fmt.Errorf("condition is no LengthEqualsCondition")
+	}
+	length, err := fieldLength(obj, c.Field)
+	return err == nil && length == c.Length, err
+}
+
+func applyLengthGreaterThan(obj any, condition filter.Condition) (bool, error) {
+	c, ok := condition.(*LengthGreaterThanCondition)
+	if !ok {
+		return false, fmt.Errorf("condition is no LengthGreaterThanCondition")
+	}
+	length, err := fieldLength(obj, c.Field)
+	return err == nil && length > c.Length, err
+}
+
+func applyLengthLowerThan(obj any, condition filter.Condition) (bool, error) {
+	c, ok := condition.(*LengthLowerThanCondition)
+	if !ok {
+		return false, fmt.Errorf("condition is no LengthLowerThanCondition")
+	}
+	length, err := fieldLength(obj, c.Field)
+	return err == nil && length < c.Length, err
+}
+
+func applyIsEmpty(obj any, condition filter.Condition) (bool, error) {
+	c, ok := condition.(*IsEmptyCondition)
+	if !ok {
+		return false, fmt.Errorf("condition is no IsEmptyCondition")
+	}
+	length, err := fieldLength(obj, c.Field)
+	return err == nil && length == 0, err
+}
+
+func applyNotEmpty(obj any, condition filter.Condition) (bool, error) {
+	c, ok := condition.(*NotEmptyCondition)
+	if !ok {
+		return false, fmt.Errorf("condition is no NotEmptyCondition")
+	}
+	length, err := fieldLength(obj, c.Field)
+	return err == nil && length > 0, err
+}
+
+// fieldLength returns the length of the slice, array, map or string field of the object.
+// Strings are measured in characters and nil values have a length of 0.
+func fieldLength(obj any, name string) (int, error) {
+	field, err := getField(obj, name)
+	if err != nil {
+		return 0, err
+	}
+	field = indirect(field)
+	if !field.IsValid() {
+		return 0, nil
+	}
+	switch field.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return field.Len(), nil
+	case reflect.String:
+		return utf8.RuneCountInString(field.String()), nil
+	}
+	return 0, fmt.Errorf("field must be of type slice/array/map/string but is of type %s", field.Kind())
+}
@@ -0,0 +1,61 @@
+package filterobject
+
+import (
+	"github.com/stretchr/testify/require"
+	"github.com/xafelium/filter"
+	"testing"
+)
+
+type LengthTestObject struct {
+	Name      string
+	Nicknames []string
+	Slots     [2]int
+	Labels    map[string]any
+	Tags      *[]string
+	Count     int
+}
+
+func TestApplyLengthConditions(t *testing.T) {
+	tags := []string{"a"}
+	obj := LengthTestObject{
+		Name:      "Zoë",
+		Nicknames: []string{"a", "b", "c"},
+		Labels:    map[string]any{},
+		Tags:      &tags,
+	}
+	for condition, expected := range map[filter.Condition]bool{
+		LengthEquals("name", 3):           true,
+		LengthEquals("name", 4):           false,
+		LengthGreaterThan("nicknames", 2): true,
+		LengthGreaterThan("nicknames", 3): false,
+		LengthLowerThan("slots", 3):       true,
+		LengthLowerThan("slots", 2):       false,
+		LengthEquals("tags", 1):           true,
+		IsEmpty("labels"):                 true,
+		IsEmpty("nicknames"):              false,
+		NotEmpty("name"):                  true,
+		NotEmpty("labels"):                false,
+		filter.Not(IsEmpty("tags")):       true,
+	} {
+		applies, err := FilterApplies(obj, condition)
+		require.NoError(t, err, condition.String())
+		require.Equal(t, expected, applies, condition.String())
+	}
+
+	for condition, expected := range map[filter.Condition]bool{
+		IsEmpty("tags"):         true,
+		LengthEquals("tags", 0): true,
+		NotEmpty("nicknames"):   false,
+	} {
+		applies, err := FilterApplies(LengthTestObject{}, condition)
+		require.NoError(t, err, condition.String())
+		require.Equal(t, expected, applies, condition.String())
+	}
+
+	applies, err := FilterApplies(map[string]any{"labels": map[string]any{"env": "prod"}}, LengthEquals("labels", 1))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	_, err = FilterApplies(obj, IsEmpty("count"))
+	require.EqualError(t, err, "field must be of type slice/array/map/string but is of type int")
+}
@@ -0,0 +1,312 @@
+package filterobject
+
+import (
+	"context"
+	"fmt"
+	"github.com/xafelium/filter"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MatchError contains the errors of the conditions which could not be evaluated by Matcher.Match.
+type MatchError struct {
+	Errors map[string]error
+}
+
+// Error returns the error message.
+func (e *MatchError) Error() string {
+	ids := make([]string, 0, len(e.Errors))
+	for id := range e.Errors {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	messages := make([]string, 0, len(ids))
+	for _, id := range ids {
+		messages = append(messages, fmt.Sprintf("%s: %s", id, e.Errors[id]))
+	}
+	return "conditions could not be evaluated: " + strings.Join(messages, "; ")
+}
+
+// Matcher finds the conditions which apply to an object out of many registered conditions.
+// Equals and In conditions which are required by a condition are indexed, so only conditions
+// whose required values match the object are evaluated. Identical sub-conditions of different
+// conditions are evaluated only once per object.
+//
+// A Matcher is safe for concurrent use.
+type Matcher struct {
+	mutex      sync.RWMutex
+	conditions map[string]*CompiledCondition
+	indexed    map[string]matcherIndexEntry
+	fields     map[string]*matcherFieldIndex
+	unindexed  map[string]struct{}
+}
+
+type matcherIndexEntry struct {
+	field   string
+	keys    []matcherKey
+	unkeyed []indexCategory
+}
+
+type matcherKey struct {
+	category indexCategory
+	key      string
+}
+
+type matcherFieldIndex struct {
+	ids     map[matcherKey]map[string]struct{}
+	unkeyed map[indexCategory]map[string]struct{}
+	all     map[string]struct{}
+}
+
+// NewMatcher creates a new Matcher.
+func NewMatcher() *Matcher {
+	return &Matcher{
+		conditions: make(map[string]*CompiledCondition),
+		indexed:    make(map[string]matcherIndexEntry),
+		fields:     make(map[string]*matcherFieldIndex),
+		unindexed:  make(map[string]struct{}),
+	}
+}
+
+// Add registers the condition with the id. An existing condition with the same id is replaced.
+func (m *Matcher) Add(id string, condition filter.Condition) error {
+	normalized := Normalize(condition)
+	compiled, err := Compile(normalized, func(o *compileOptions) {
+		o.memoize = true
+	})
+	if err != nil {
+		return err
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.remove(id)
+	m.conditions[id] = compiled
+
+	field, values, ok := requiredMembership(normalized)
+	if !ok {
+		m.unindexed[id] = struct{}{}
+		return nil
+	}
+	entry := matcherIndexEntry{field: field}
+	unkeyed := make(map[indexCategory]bool)
+	for _, v := range values {
+		keys, categories, ok := operandKeys(reflect.ValueOf(v))
+		if !ok {
+			m.unindexed[id] = struct{}{}
+			return nil
+		}
+		entry.keys = append(entry.keys, keys...)
+		for _, category := range categories {
+			if !unkeyed[category] {
+				unkeyed[category] = true
+				entry.unkeyed = append(entry.unkeyed, category)
+			}
+		}
+	}
+	index, found := m.fields[field]
+	if !found {
+		index = &matcherFieldIndex{
+			ids:     make(map[matcherKey]map[string]struct{}),
+			unkeyed: make(map[indexCategory]map[string]struct{}),
+			all:     make(map[string]struct{}),
+		}
+		m.fields[field] = index
+	}
+	for _, key := range entry.keys {
+		if index.ids[key] == nil {
+			index.ids[key] = make(map[string]struct{})
+		}
+		index.ids[key][id] = struct{}{}
+	}
+	for _, category := range entry.unkeyed {
+		if index.unkeyed[category] == nil {
+			index.unkeyed[category] = make(map[string]struct{})
+		}
+		index.unkeyed[category][id] = struct{}{}
+	}
+	index.all[id] = struct{}{}
+	m.indexed[id] = entry
+	return nil
+}
+
+// Remove unregisters the condition with the id.
+func (m *Matcher) Remove(id string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.remove(id)
+}
+
+func (m *Matcher) remove(id string) {
+	delete(m.conditions, id)
+	delete(m.unindexed, id)
+	entry, found := m.indexed[id]
+	if !found {
+		return
+	}
+	delete(m.indexed, id)
+	index := m.fields[entry.field]
+	for _, key := range entry.keys {
+		delete(index.ids[key], id)
+		if len(index.ids[key]) == 0 {
+			delete(index.ids, key)
+		}
+	}
+	for _, category := range entry.unkeyed {
+		delete(index.unkeyed[category], id)
+		if len(index.unkeyed[category]) == 0 {
+			delete(index.unkeyed, category)
+		}
+	}
+	delete(index.all, id)
+	if len(index.all) == 0 {
+		delete(m.fields, entry.field)
+	}
+}
+
+// Len returns the number of registered conditions.
+func (m *Matcher) Len() int {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return len(m.conditions)
+}
+
+// Match returns the sorted ids of all conditions which apply to the object.
+// If conditions cannot be evaluated, the ids of the other matching conditions are returned
+// together with a *MatchError.
+func (m *Matcher) Match(obj any) ([]string, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	candidates := make(map[string]struct{}, len(m.unindexed))
+	for id := range m.unindexed {
+		candidates[id] = struct{}{}
+	}
+	for field, index := range m.fields {
+		value, err := getField(obj, field)
+		if err != nil {
+			for id := range index.all {
+				candidates[id] = struct{}{}
+			}
+			continue
+		}
+		value = indirect(value)
+		if !value.IsValid() {
+			continue
+		}
+		category := indexCategoryOf(value.Type())
+		key, ok := indexKey(category, value)
+		if !ok {
+			for id := range index.all {
+				candidates[id] = struct{}{}
+			}
+			continue
+		}
+		for id := range index.ids[matcherKey{category: category, key: key}] {
+			candidates[id] = struct{}{}
+		}
+		for id := range index.unkeyed[category] {
+			candidates[id] = struct{}{}
+		}
+	}
+
+	ctx := context.WithValue(context.Background(), memoContextKey{}, make(memo))
+	var ids []string
+	var matchErr *MatchError
+	for id := range candidates {
+		applies, err := m.conditions[id].AppliesContext(ctx, obj)
+		if err != nil {
+			if matchErr == nil {
+				matchErr = &MatchError{Errors: make(map[string]error)}
+			}
+			matchErr.Errors[id] = err
+			continue
+		}
+		if applies {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	if matchErr != nil {
+		return ids, matchErr
+	}
+	return ids, nil
+}
+
+// requiredMembership returns the field and the values of an Equals or In condition which must
+// apply for the normalized condition to apply.
+func requiredMembership(condition filter.Condition) (string, []any, bool) {
+	condition = filter.UnwrapWhere(condition)
+	if field, values, ok := membership(condition, false); ok {
+		return field, values, true
+	}
+	and, ok := condition.(*filter.AndCondition)
+	if !ok {
+		return "", nil, false
+	}
+	for _, c := range and.Conditions {
+		if field, values, ok := membership(c, false); ok {
+			return field, values, true
+		}
+	}
+	return "", nil, false
+}
+
+// operandKeys returns the keys of the value for all index categories it can be equal to.
+// Strings can also be equal to values which are parsed from them, like times and durations,
+// so the categories without a key are returned as unkeyed categories.
+// Values which are compared by enums or custom methods cannot be indexed.
+func operandKeys(v reflect.Value) ([]matcherKey, []indexCategory, bool) {
+	v = indirect(v)
+	if !v.IsValid() || indexCategoryOf(v.Type()) == noCategory {
+		return nil, nil, false
+	}
+	var keys []matcherKey
+	var unkeyed []indexCategory
+	for _, category := range []indexCategory{numberCategory, stringCategory, timeCategory, boolCategory} {
+		if key, ok := indexKey(category, v); ok {
+			keys = append(keys, matcherKey{category: category, key: key})
+		} else if v.Kind() == reflect.String {
+			unkeyed = append(unkeyed, category)
+		}
+	}
+	return keys, unkeyed, true
+}
+
+type memoContextKey struct{}
+
+// memo contains the results of conditions which were already evaluated for an object.
+type memo map[string]memoResult
+
+type memoResult struct {
+	applies bool
+	err     error
+}
+
+// memoNode evaluates its condition only once per object if a memo is present in the context.
+type memoNode struct {
+	key   string
+	child node
+}
+
+func (n *memoNode) applies(ctx context.Context, obj any) (bool, error) {
+	results, ok := ctx.Value(memoContextKey{}).(memo)
+	if !ok {
+		return n.child.applies(ctx, obj)
+	}
+	if result, found := results[n.key]; found {
+		return result.applies, result.err
+	}
+	applies, err := n.child.applies(ctx, obj)
+	results[n.key] = memoResult{applies: applies, err: err}
+	return applies, err
+}
+
+func (n *memoNode) cost() float64 {
+	return n.child.cost()
+}
+
+func (n *memoNode) String() string {
+	return n.child.String()
+}
@@ -0,0 +1,142 @@
+package filterobject
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"github.com/xafelium/filter"
+	"testing"
+	"time"
+)
+
+func TestMatcher(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	conditions := map[string]filter.Condition{
+		"magic":          filter.Where(filter.Equals("taskType", "magic")),
+		"magic-or-chore": filter.Or(filter.Equals("taskType", "magic"), filter.Equals("taskType", "chore")),
+		"magic-a":        filter.And(filter.Equals("taskType", "magic"), filter.Regex("name", "^a")),
+		"ids":            filter.In("id", []any{1, "2", 3.0}),
+		"not-magic":      filter.Not(filter.Equals("taskType", "magic")),
+		"house":          filter.ArrayContains("houseIds", 3),
+		"created":        filter.Equals("createdAt", "2024-01-01"),
+		"created-after":  filter.GreaterThan("createdAt", start.Add(time.Hour)),
+		"child":          filter.Equals("childObject", nil),
+		"unknown":        filter.Equals("unknownField", 1),
+	}
+	matcher := NewMatcher()
+	for id, condition := range conditions {
+		require.NoError(t, matcher.Add(id, condition))
+	}
+	require.Equal(t, len(conditions), matcher.Len())
+
+	objects := []TestObject{
+		{Id: 1, TaskType: "magic", Name: "alice", CreatedAt: start},
+		{Id: 2, TaskType: "chore", Name: "bob", HouseIds: []int{3}, CreatedAt: start.Add(2 * time.Hour)},
+		{Id: 4, TaskType: "magic", Name: "bob", ChildObject: &TestObject{}},
+		{Id: 3, TaskType: "other"},
+	}
+	for _, obj := range objects {
+		ids, err := matcher.Match(obj)
+		var matchErr *MatchError
+		require.ErrorAs(t, err, &matchErr)
+		require.Len(t, matchErr.Errors, 1)
+		require.EqualError(t, matchErr.Errors["unknown"], "field 'unknownField' was not found on object")
+
+		var expected []string
+		for id, condition := range conditions {
+			applies, err := FilterApplies(obj, condition)
+			if err == nil && applies {
+				expected = append(expected, id)
+			}
+		}
+		require.ElementsMatch(t, expected, ids)
+		require.IsIncreasing(t, ids)
+	}
+}
+
+func TestMatcherIndex(t *testing.T) {
+	matcher := NewMatcher()
+	require.NoError(t, matcher.Add("magic", filter.Equals("taskType", "magic")))
+	require.NoError(t, matcher.Add("ids", filter.And(filter.In("id", []int{1, 2}), filter.Regex("name", "^a"))))
+	require.NoError(t, matcher.Add("regex", filter.Regex("name", "^b")))
+
+	require.Len(t, matcher.unindexed, 1)
+	require.Len(t, matcher.fields, 2)
+	require.Len(t, matcher.fields["id"].ids, 2)
+
+	ids, err := matcher.Match(TestObject{Id: 2, TaskType: "magic", Name: "alice"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"ids", "magic"}, ids)
+
+	ids, err = matcher.Match(&TestObject{Id: 3, TaskType: "chore", Name: "bob"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"regex"}, ids)
+}
+
+func TestMatcherSharedEvaluation(t *testing.T) {
+	metrics := NewMetrics()
+	matcher := NewMatcher()
+	regex := filter.Regex("name", "^a")
+	require.NoError(t, matcher.Add("a", filter.And(regex, filter.Equals("taskType", "magic"))))
+	require.NoError(t, matcher.Add("b", filter.And(filter.Equals("taskType", "magic"), regex)))
+	require.NoError(t, matcher.Add("c", filter.Or(regex, filter.Equals("id", 1))))
+
+	node, ok := matcher.conditions["a"].root.(*memoNode)
+	require.True(t, ok)
+	ctx := context.WithValue(context.Background(), memoContextKey{}, make(memo))
+	for _, id := range []string{"a", "b", "c"} {
+		compiled, err := Compile(matcher.conditions[id].Condition(), WithObserver(metrics), func(o *compileOptions) {
+			o.memoize = true
+		})
+		require.NoError(t, err)
+		applies, err := compiled.AppliesContext(ctx, TestObject{TaskType: "magic", Name: "alice"})
+		require.NoError(t, err)
+		require.True(t, applies)
+	}
+	stats := metrics.Snapshot()
+	require.Equal(t, int64(1), stats[filter.AndConditionType].Evaluations)
+	require.Equal(t, int64(1), stats[filter.RegexConditionType].Evaluations)
+	require.Equal(t, int64(2), stats[filter.EqualsConditionType].Evaluations)
+	require.Equal(t, "(taskType = magic) and (name matchesRegex(^a))", node.String())
+}
+
+func TestMatcherDistinguishesValues(t *testing.T) {
+	matcher := NewMatcher()
+	require.NoError(t, matcher.Add("a", filter.And(filter.GreaterThan("id", sameGoString(1)), filter.Regex("name", "^a"))))
+	require.NoError(t, matcher.Add("b", filter.And(filter.GreaterThan("id", sameGoString(3)), filter.Regex("name", "^a"))))
+
+	ids, err := matcher.Match(TestObject{Id: 2, Name: "alice"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a"}, ids)
+}
+
+func TestMatcherReplaceAndRemove(t *testing.T) {
+	matcher := NewMatcher()
+	require.NoError(t, matcher.Add("a", filter.Equals("taskType", "magic")))
+	require.NoError(t, matcher.Add("a", filter.Equals("taskType", "chore")))
+	require.Equal(t, 1, matcher.Len())
+
+	ids, err := matcher.Match(TestObject{TaskType: "magic"})
+	require.NoError(t, err)
+	require.Empty(t, ids)
+
+	ids, err = matcher.Match(TestObject{TaskType: "chore"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a"}, ids)
+
+	matcher.Remove("a")
+	require.Equal(t, 0, matcher.Len())
+	require.Empty(t, matcher.fields)
+	ids, err = matcher.Match(TestObject{TaskType: "chore"})
+	require.NoError(t, err)
+	require.Empty(t, ids)
+
+	require.Error(t, matcher.Add("b", filter.Not(&unknownCondition{})))
+	require.Equal(t, 0, matcher.Len())
+
+	require.NoError(t, matcher.Add("c", filter.And(nil, filter.Equals("id", 1))))
+	require.NoError(t, matcher.Add("d", filter.Or(filter.Group(nil), filter.Equals("id", 2))))
+	require.NoError(t, matcher.Add("e", filter.Not(nil)))
+	ids, err = matcher.Match(TestObject{Id: 1})
+	require.NoError(t, err)
+	require.Equal(t, []string{"c", "d"}, ids)
+}
@@ -0,0 +1,101 @@
+package filterobject
+
+import (
+	"github.com/xafelium/filter"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// MultiValueMode defines how conditions on fields with wildcard segments like
+// "addresses[*].country", which have multiple values, are evaluated.
+type MultiValueMode int
+
+const (
+	// AnyValue conditions apply if they apply to at least one value of the field.
+	// They do not apply to fields without values.
+	AnyValue MultiValueMode = iota
+	// AllValues conditions apply if they apply to every value of the field.
+	// They apply to fields without values.
+	AllValues
+)
+
+// WithMultiValueMode sets how conditions on fields with multiple values are evaluated.
+// Conditions are evaluated with AnyValue by default and by FilterApplies.
+func WithMultiValueMode(mode MultiValueMode) CompileOption {
+	return func(o *compileOptions) {
+		o.multiValueMode = mode
+	}
+}
+
+// boundField is an object whose field with the name is bound to one of its values, so
+// evaluators, which resolve the field with getField, evaluate the condition for that value.
+type boundField struct {
+	obj   any
+	name  string
+	value reflect.Value
+}
+
+// conditionField returns the field of conditions which have one.
+func conditionField(condition filter.Condition) (string, bool) {
+	v := reflect.ValueOf(condition)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return "", false
+	}
+	index, ok := conditionFieldIndexes.Load(v.Type())
+	if !ok {
+		index = -1
+		if field, ok := v.Type().Elem().FieldByName("Field"); ok && len(field.Index) == 1 && field.Type.Kind() == reflect.String {
+			index = field.Index[0]
+		}
+		conditionFieldIndexes.Store(v.Type(), index)
+	}
+	if index.(int) < 0 {
+		return "", false
+	}
+	return v.Elem().Field(index.(int)).String(), true
+}
+
+// conditionFieldIndexes caches the index of the Field field by the type of the condition.
+var conditionFieldIndexes sync.Map
+
+// multiValuedField returns the field of the condition if it has multiple values.
+func multiValuedField(condition filter.Condition) (string, bool) {
+	field, ok := conditionField(condition)
+	if !ok || !isMultiValued(field) {
+		return "", false
+	}
+	return field, true
+}
+
+// isMultiValued reports whether the field has wildcard segments.
+func isMultiValued(field string) bool {
+	if !strings.Contains(field, "[*]") {
+		return false
+	}
+	segments, err := fieldPath(field)
+	return err == nil && hasWildcard(segments)
+}
+
+// applyMultiValued evaluates the condition for every value of the field of the object.
+// If the field is already bound to a value, e.g. since an evaluator evaluates another condition
+// on the same field, the condition is evaluated for that value.
+func applyMultiValued(obj any, field string, mode MultiValueMode, evaluate func(obj any) (bool, error)) (bool, error) {
+	if bound, ok := obj.(*boundField); ok && bound.name == field {
+		return evaluate(obj)
+	}
+	values, err := getFieldValues(obj, field)
+	if err != nil {
+		return false, err
+	}
+	for _, value := range values {
+		applies, err := evaluate(&boundField{obj: obj, name: field, value: value})
+		if err != nil {
+			return false, err
+		}
+		if applies == (mode == AnyValue) {
+			return applies, nil
+		}
+	}
+	return mode == AllValues, nil
+}
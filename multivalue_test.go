@@ -0,0 +1,115 @@
+package filterobject
+
+import (
+	"github.com/stretchr/testify/require"
+	"github.com/xafelium/filter"
+	"reflect"
+	"testing"
+)
+
+type Order struct {
+	Id        int
+	Items     []OrderItem
+	Addresses []*Address
+	Customer  *Customer
+	Totals    map[string]int
+}
+
+type OrderItem struct {
+	Sku  string
+	Tags []string
+}
+
+type Address struct {
+	Country string
+}
+
+type Customer struct {
+	Addresses []Address
+}
+
+func TestMultiValuedFields(t *testing.T) {
+	order := Order{
+		Items:     []OrderItem{{Sku: "a", Tags: []string{"x"}}, {Sku: "b", Tags: []string{"y", "z"}}},
+		Addresses: []*Address{{Country: "de"}, nil, {Country: "fr"}},
+		Totals:    map[string]int{"net": 10, "gross": 12},
+	}
+	tests := []struct {
+		condition filter.Condition
+		any       bool
+		all       bool
+	}{
+		{condition: filter.Equals("items[0].sku", "a"), any: true, all: true},
+		{condition: filter.Equals("items[-1].sku", "b"), any: true, all: true},
+		{condition: filter.IsNil("items[2].sku"), any: true, all: true},
+		{condition: filter.IsNil("items[-3]"), any: true, all: true},
+		{condition: filter.ArrayContains("items[1].tags", "z"), any: true, all: true},
+		{condition: filter.Equals("items[*].sku", "b"), any: true, all: false},
+		{condition: filter.In("items[*].sku", []string{"a", "b"}), any: true, all: true},
+		{condition: filter.Equals("items[*].tags[*]", "z"), any: true, all: false},
+		{condition: filter.ArrayContains("items[*].tags", "x"), any: true, all: false},
+		{condition: LengthGreaterThan("items[*].tags", 0), any: true, all: true},
+		{condition: filter.Equals("addresses[*].country", "fr"), any: true, all: false},
+		{condition: filter.IsNil("addresses[*].country"), any: true, all: false},
+		{condition: filter.Not(filter.Equals("addresses[*].country", "fr")), any: false, all: true},
+		{condition: filter.NotEquals("addresses[*].country", "fr"), any: true, all: false},
+		{condition: filter.Equals("customer.addresses[*].country", "de"), any: false, all: true},
+		{condition: filter.GreaterThan("totals[*]", 11), any: true, all: false},
+	}
+	for _, test := range tests {
+		t.Run(test.condition.String(), func(t *testing.T) {
+			applies, err := FilterApplies(order, test.condition)
+			require.NoError(t, err)
+			require.Equal(t, test.any, applies, "any")
+
+			compiled, err := Compile(test.condition)
+			require.NoError(t, err)
+			applies, err = compiled.Applies(order)
+			require.NoError(t, err)
+			require.Equal(t, test.any, applies, "any")
+
+			compiled, err = Compile(test.condition, WithMultiValueMode(AllValues))
+			require.NoError(t, err)
+			applies, err = compiled.Applies(order)
+			require.NoError(t, err)
+			require.Equal(t, test.all, applies, "all")
+
+			applies, err = FilterApplies(order, Normalize(test.condition))
+			require.NoError(t, err)
+			require.Equal(t, test.any, applies, "normalized")
+		})
+	}
+}
+
+func TestMultiValuedFieldsWithCoercion(t *testing.T) {
+	condition, err := UnmarshalCondition([]byte(`{"type":"or","conditions":[
+		{"type":"equals","field":"items[*].sku","value":"c"},
+		{"type":"in","field":"totals[*]","value":[12.0]}
+	]}`))
+	require.NoError(t, err)
+	compiled, err := Compile(condition, WithCoercion())
+	require.NoError(t, err)
+	applies, err := compiled.Applies(Order{Totals: map[string]int{"gross": 12}})
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	coerced, err := CoerceCondition(condition, reflect.TypeOf(Order{}))
+	require.NoError(t, err)
+	require.Equal(t, filter.Or(filter.Equals("items[*].sku", "c"), filter.In("totals[*]", []int{12})), coerced)
+
+	_, err = FilterApplies(Order{}, filter.Equals("id[0]", 1))
+	require.EqualError(t, err, "field 'id[0]' cannot be indexed since it is of type int")
+
+	_, err = FilterApplies(Order{}, filter.Equals("items[*].price", 1))
+	require.EqualError(t, err, "field 'items[*].price' was not found on object")
+
+	_, err = Project(Order{}, "items[*].sku")
+	require.EqualError(t, err, "field 'items[*].sku' has multiple values")
+
+	projection, err := Project(Order{Items: []OrderItem{{Sku: "a"}}}, "items[0].sku", "items[-1].sku AS lastSku", "items[-2].sku AS secondLastSku")
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"items": map[string]any{"0": map[string]any{"sku": "a"}}, "lastSku": "a", "secondLastSku": nil}, projection)
+
+	_, err = Project(Order{}, "items[-2].sku")
+	require.EqualError(t, err, "field 'items[-2].sku' cannot be projected since it has a negative index")
+}
@@ -0,0 +1,351 @@
+package filterobject
+
+import (
+	"fmt"
+	"github.com/xafelium/filter"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Normalize returns a condition which is equivalent to the given condition but cheaper to evaluate.
+// Groups are removed, negations are pushed down to the leaf conditions, nested And and Or
+// conditions are merged, duplicate sub-conditions are removed and the remaining sub-conditions
+// are ordered deterministically. Equals conditions on the same field within an Or condition are
+// merged into a single In condition.
+//
+// Since sub-conditions are reordered, errors of sub-conditions may not be reported anymore
+// if another sub-condition already decides the result. Nil sub-conditions are kept as they are.
+func Normalize(condition filter.Condition) filter.Condition {
+	if condition == nil {
+		return nil
+	}
+	if where, ok := condition.(*filter.WhereCondition); ok {
+		return filter.Where(Normalize(where.Condition))
+	}
+	return normalize(condition, false)
+}
+
+func normalize(condition filter.Condition, negate bool) filter.Condition {
+	if isNilCondition(condition) {
+		if negate {
+			return filter.Not(condition)
+		}
+		return condition
+	}
+	switch c := condition.(type) {
+	case *filter.WhereCondition:
+		if c.Condition == nil {
+			if negate {
+				return filter.Not(condition)
+			}
+			return condition
+		}
+		return normalize(c.Condition, negate)
+	case *filter.GroupCondition:
+		return normalize(c.Condition, negate)
+	case *filter.NotCondition:
+		return normalize(c.Condition, !negate)
+	case *filter.AndCondition:
+		if negate {
+			return normalizeJunction(filter.OrConditionType, c.Conditions, true)
+		}
+		return normalizeJunction(filter.AndConditionType, c.Conditions, false)
+	case *filter.OrCondition:
+		if negate {
+			return normalizeJunction(filter.AndConditionType, c.Conditions, true)
+		}
+		return normalizeJunction(filter.OrConditionType, c.Conditions, false)
+	}
+	if !negate {
+		return condition
+	}
+	if _, ok := multiValuedField(condition); ok {
+		// A negated condition on any value of a field is not the negation on any value.
+		return filter.Not(condition)
+	}
+	switch c := condition.(type) {
+	case *filter.EqualsCondition:
+		return filter.NotEquals(c.Field, c.Value)
+	case *filter.NotEqualsCondition:
+		return filter.Equals(c.Field, c.Value)
+	case *filter.RegexCondition:
+		return filter.NotRegex(c.Field, c.Expression)
+	case *filter.NotRegexCondition:
+		return filter.Regex(c.Field, c.Expression)
+	case *filter.IsNilCondition:
+		return filter.NotNil(c.Field)
+	case *filter.NotNilCondition:
+		return filter.IsNil(c.Field)
+	case *IsEmptyCondition:
+		return NotEmpty(c.Field)
+	case *NotEmptyCondition:
+		return IsEmpty(c.Field)
+	default:
+		return filter.Not(condition)
+	}
+}
+
+// normalizeJunction normalizes the sub-conditions of an And or Or condition of the given type.
+func normalizeJunction(conditionType string, conditions []filter.Condition, negate bool) filter.Condition {
+	var flattened []filter.Condition
+	for _, c := range conditions {
+		normalized := normalize(c, negate)
+		if !isNilCondition(normalized) && normalized.Type() == conditionType {
+			flattened = append(flattened, junctionConditions(normalized)...)
+		} else {
+			flattened = append(flattened, normalized)
+		}
+	}
+	if conditionType == filter.OrConditionType {
+		flattened = mergeEquals(flattened, false)
+	} else {
+		flattened = mergeEquals(flattened, true)
+	}
+
+	seen := make(map[string]struct{}, len(flattened))
+	keys := make(map[filter.Condition]string, len(flattened))
+	var unique []filter.Condition
+	for _, c := range flattened {
+		key := conditionKey(c)
+		if _, found := seen[key]; found {
+			continue
+		}
+		seen[key] = struct{}{}
+		keys[c] = key
+		unique = append(unique, c)
+	}
+	sort.SliceStable(unique, func(i, j int) bool {
+		return keys[unique[i]] < keys[unique[j]]
+	})
+
+	if len(unique) == 1 {
+		return unique[0]
+	}
+	if conditionType == filter.OrConditionType {
+		return filter.Or(unique...)
+	}
+	return filter.And(unique...)
+}
+
+// isNilCondition reports whether the condition is nil or a nil pointer.
+func isNilCondition(condition filter.Condition) bool {
+	return condition == nil || (reflect.ValueOf(condition).Kind() == reflect.Ptr && reflect.ValueOf(condition).IsNil())
+}
+
+func junctionConditions(c filter.Condition) []filter.Condition {
+	switch junction := c.(type) {
+	case *filter.AndCondition:
+		return junction.Conditions
+	case *filter.OrCondition:
+		return junction.Conditions
+	default:
+		return []filter.Condition{c}
+	}
+}
+
+// mergeEquals merges Equals and In conditions on the same field into a single In condition.
+// If negated is true, NotEquals and negated In conditions are merged into a negated In condition.
+// Conditions on fields with multiple values are not merged.
+func mergeEquals(conditions []filter.Condition, negated bool) []filter.Condition {
+	values := make(map[string][]any)
+	counts := make(map[string]int)
+	for _, c := range conditions {
+		if field, v, ok := membership(c, negated); ok && !isMultiValued(field) {
+			values[field] = append(values[field], v...)
+			counts[field]++
+		}
+	}
+
+	var merged []filter.Condition
+	for _, c := range conditions {
+		field, _, ok := membership(c, negated)
+		if !ok || counts[field] < 2 {
+			merged = append(merged, c)
+			continue
+		}
+		if v, found := values[field]; found {
+			var in filter.Condition = filter.In(field, uniqueValues(v))
+			if negated {
+				in = filter.Not(in)
+			}
+			merged = append(merged, in)
+			delete(values, field)
+		}
+	}
+	return merged
+}
+
+// membership returns the field and the values of Equals and In conditions (or NotEquals and
+// negated In conditions, if negated is true).
+func membership(c filter.Condition, negated bool) (string, []any, bool) {
+	if negated {
+		switch n := c.(type) {
+		case *filter.NotEqualsCondition:
+			return n.Field, []any{n.Value}, true
+		case *filter.NotCondition:
+			if in, ok := n.Condition.(*filter.InCondition); ok {
+				return inMembership(in)
+			}
+		}
+		return "", nil, false
+	}
+	switch m := c.(type) {
+	case *filter.EqualsCondition:
+		return m.Field, []any{m.Value}, true
+	case *filter.InCondition:
+		return inMembership(m)
+	}
+	return "", nil, false
+}
+
+func inMembership(in *filter.InCondition) (string, []any, bool) {
+	v := reflect.ValueOf(in.Value)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return "", nil, false
+	}
+	values := make([]any, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		values = append(values, v.Index(i).Interface())
+	}
+	return in.Field, values, true
+}
+
+// uniqueValues removes duplicate values and orders the remaining values deterministically.
+func uniqueValues(values []any) []any {
+	keys := make(map[string]any, len(values))
+	for _, v := range values {
+		keys[valueKey(v)] = v
+	}
+	sortedKeys := make([]string, 0, len(keys))
+	for key := range keys {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+	unique := make([]any, 0, len(keys))
+	for _, key := range sortedKeys {
+		unique = append(unique, keys[key])
+	}
+	return unique
+}
+
+// conditionKey returns a string identifying the condition. Conditions with equal keys are equivalent.
+func conditionKey(c filter.Condition) string {
+	if isNilCondition(c) {
+		return "nil"
+	}
+	switch composite := c.(type) {
+	case *filter.AndCondition, *filter.OrCondition:
+		var keys []string
+		for _, sub := range junctionConditions(composite) {
+			keys = append(keys, conditionKey(sub))
+		}
+		return c.Type() + "(" + strings.Join(keys, ",") + ")"
+	case *filter.NotCondition:
+		return c.Type() + "(" + conditionKey(composite.Condition) + ")"
+	case *filter.GroupCondition:
+		return c.Type() + "(" + conditionKey(composite.Condition) + ")"
+	case *filter.WhereCondition:
+		if composite.Condition == nil {
+			return c.Type() + "()"
+		}
+		return c.Type() + "(" + conditionKey(composite.Condition) + ")"
+	default:
+		return c.Type() + valueKey(c)
+	}
+}
+
+// valueKey returns a string identifying the value by its type and structure. Pointers are
+// identified by the values they point to and methods of the value like GoString are not used,
+// so values with equal keys are equal.
+func valueKey(v any) string {
+	var b strings.Builder
+	writeValueKey(&b, reflect.ValueOf(v), map[uintptr]bool{})
+	return b.String()
+}
+
+func writeValueKey(b *strings.Builder, v reflect.Value, visited map[uintptr]bool) {
+	if !v.IsValid() {
+		b.WriteString("nil")
+		return
+	}
+	b.WriteString(typeKey(v.Type()))
+	b.WriteByte('(')
+	defer b.WriteByte(')')
+	if v.Type() == timeType && v.CanInterface() {
+		t := v.Interface().(time.Time)
+		b.WriteString(t.Format(time.RFC3339Nano) + " " + t.Location().String())
+		return
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		b.WriteString(strconv.FormatBool(v.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		b.WriteString(strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		b.WriteString(strconv.FormatUint(v.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		b.WriteString(strconv.FormatFloat(v.Float(), 'g', -1, 64))
+	case reflect.Complex64, reflect.Complex128:
+		b.WriteString(strconv.FormatComplex(v.Complex(), 'g', -1, 128))
+	case reflect.String:
+		b.WriteString(strconv.Quote(v.String()))
+	case reflect.Ptr:
+		if v.IsNil() {
+			b.WriteString("nil")
+			return
+		}
+		if visited[v.Pointer()] {
+			// Cyclic values are identified by their address.
+			fmt.Fprintf(b, "%#x", v.Pointer())
+			return
+		}
+		visited[v.Pointer()] = true
+		defer delete(visited, v.Pointer())
+		writeValueKey(b, v.Elem(), visited)
+	case reflect.Interface:
+		writeValueKey(b, v.Elem(), visited)
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			b.WriteString("nil")
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			writeValueKey(b, v.Index(i), visited)
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			b.WriteString("nil")
+			return
+		}
+		entries := make([]string, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			var entry strings.Builder
+			writeValueKey(&entry, iter.Key(), visited)
+			entry.WriteByte(':')
+			writeValueKey(&entry, iter.Value(), visited)
+			entries = append(entries, entry.String())
+		}
+		sort.Strings(entries)
+		b.WriteString(strings.Join(entries, ""))
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			b.WriteString(v.Type().Field(i).Name + ":")
+			writeValueKey(b, v.Field(i), visited)
+		}
+	default:
+		// Functions, channels and unsafe pointers are identified by their address.
+		fmt.Fprintf(b, "%#x", v.Pointer())
+	}
+}
+
+// typeKey returns a string identifying the type. Named types are qualified by their package path.
+func typeKey(t reflect.Type) string {
+	if t.Name() != "" && t.PkgPath() != "" {
+		return t.PkgPath() + "." + t.Name()
+	}
+	return t.String()
+}
@@ -0,0 +1,159 @@
+package filterobject
+
+import (
+	"github.com/stretchr/testify/require"
+	"github.com/xafelium/filter"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition filter.Condition
+		expected  filter.Condition
+	}{
+		{
+			name:      "nil",
+			condition: nil,
+			expected:  nil,
+		},
+		{
+			name:      "empty where",
+			condition: filter.Where(nil),
+			expected:  filter.Where(nil),
+		},
+		{
+			name:      "leaf condition",
+			condition: filter.Equals("id", 1),
+			expected:  filter.Equals("id", 1),
+		},
+		{
+			name:      "nested groups",
+			condition: filter.Where(filter.Group(filter.Group(filter.Equals("id", 1)))),
+			expected:  filter.Where(filter.Equals("id", 1)),
+		},
+		{
+			name: "nested and conditions",
+			condition: filter.Where(filter.Group(filter.And(
+				filter.Group(filter.Equals("name", "foo")),
+				filter.And(filter.Contains("taskType", "a"), filter.IsNil("childObject")),
+			))),
+			expected: filter.Where(filter.And(
+				filter.Contains("taskType", "a"),
+				filter.Equals("name", "foo"),
+				filter.IsNil("childObject"),
+			)),
+		},
+		{
+			name:      "double negation",
+			condition: filter.Not(filter.Group(filter.Not(filter.Regex("name", "^a")))),
+			expected:  filter.Regex("name", "^a"),
+		},
+		{
+			name:      "negated leaf conditions",
+			condition: filter.Not(filter.And(filter.Equals("id", 1), filter.Regex("name", "^a"), filter.IsNil("childObject"))),
+			expected: filter.Or(
+				filter.NotEquals("id", 1),
+				filter.NotNil("childObject"),
+				filter.NotRegex("name", "^a"),
+			),
+		},
+		{
+			name:      "de morgan",
+			condition: filter.Not(filter.Or(filter.GreaterThan("id", 1), filter.NotEquals("name", "foo"))),
+			expected:  filter.And(filter.Equals("name", "foo"), filter.Not(filter.GreaterThan("id", 1))),
+		},
+		{
+			name:      "identical branches",
+			condition: filter.Or(filter.Contains("name", "a"), filter.Group(filter.Contains("name", "a"))),
+			expected:  filter.Contains("name", "a"),
+		},
+		{
+			name:      "equals on the same field",
+			condition: filter.Or(filter.Equals("id", 2), filter.Equals("name", "foo"), filter.Equals("id", 1), filter.In("id", []int{2, 3})),
+			expected:  filter.Or(filter.Equals("name", "foo"), filter.In("id", []any{1, 2, 3})),
+		},
+		{
+			name:      "not equals on the same field",
+			condition: filter.And(filter.NotEquals("id", 1), filter.Not(filter.Equals("id", 2))),
+			expected:  filter.Not(filter.In("id", []any{1, 2})),
+		},
+		{
+			name:      "nil sub-condition",
+			condition: filter.And(nil, filter.Equals("id", 1)),
+			expected:  filter.And(filter.Equals("id", 1), nil),
+		},
+		{
+			name:      "group without condition",
+			condition: filter.Or(filter.Group(nil), filter.Equals("id", 1)),
+			expected:  filter.Or(filter.Equals("id", 1), nil),
+		},
+		{
+			name:      "negated nil sub-condition",
+			condition: filter.Not(filter.And(nil, filter.Equals("id", 1))),
+			expected:  filter.Or(filter.Not(nil), filter.NotEquals("id", 1)),
+		},
+		{
+			name:      "equal values behind different pointers",
+			condition: filter.Or(filter.NotEquals("childObject", &TestObject{Id: 1}), filter.NotEquals("childObject", &TestObject{Id: 1})),
+			expected:  filter.NotEquals("childObject", &TestObject{Id: 1}),
+		},
+		{
+			name:      "values with equal go syntax representations",
+			condition: filter.Or(filter.Equals("id", sameGoString(1)), filter.Equals("id", sameGoString(2))),
+			expected:  filter.In("id", []any{sameGoString(1), sameGoString(2)}),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test := test
+
+			actual := Normalize(test.condition)
+
+			require.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestNormalizeIsEquivalent(t *testing.T) {
+	child := &TestObject{Id: 7}
+	objects := []TestObject{
+		{Id: 1, Name: "foo", TaskType: "a", Nicknames: []string{"f"}},
+		{Id: 2, Name: "bar", TaskType: "b", ChildObject: child},
+		{Id: 3, Name: "baz", TaskType: "a", HouseIds: []int{1, 2}},
+		{Id: 4, Name: "abc", ChildObject: child},
+	}
+	conditions := []filter.Condition{
+		filter.Where(filter.Not(filter.Group(filter.And(
+			filter.Or(filter.Equals("id", 1), filter.Equals("id", 3), filter.Regex("name", "^a")),
+			filter.Not(filter.IsNil("childObject")),
+		)))),
+		filter.Where(filter.Or(
+			filter.And(filter.NotEquals("id", 1), filter.NotEquals("id", 2)),
+			filter.Group(filter.Not(filter.Not(filter.ArrayContains("houseIds", 2)))),
+		)),
+		filter.Not(filter.Or(filter.Contains("taskType", "a"), filter.Not(filter.NotRegex("name", "z$")))),
+		filter.Not(filter.Or(IsEmpty("nicknames"), filter.Not(NotEmpty("houseIds")), LengthGreaterThan("name", 3))),
+		filter.And(nil, filter.Equals("id", 1)),
+		filter.Not(filter.Or(filter.Group(nil), filter.Equals("id", 2))),
+		filter.Not(filter.Where(nil)),
+		filter.Or(filter.Not(filter.Where(nil)), filter.Equals("id", 3)),
+	}
+	for _, condition := range conditions {
+		normalized := Normalize(condition)
+		for _, obj := range objects {
+			expected, err := FilterApplies(obj, condition)
+			require.NoError(t, err)
+			actual, err := FilterApplies(obj, normalized)
+			require.NoError(t, err)
+			require.Equal(t, expected, actual, "%s / %s for %+v", condition, normalized, obj)
+		}
+	}
+}
+
+// sameGoString has the same Go syntax representation for all values.
+type sameGoString int
+
+func (sameGoString) GoString() string {
+	return "sameGoString"
+}
@@ -7,8 +7,9 @@ import (
 	"github.com/xafelium/filter"
 	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
-	"time"
+	"sync"
 )
 
 type ConditionEvaluator func(obj any, condition filter.Condition) (bool, error)
@@ -51,6 +52,11 @@ func FilterApplies(obj any, condition filter.Condition) (bool, error) {
 	if !ok {
 		return false, fmt.Errorf(fmt.Sprintf("unknown condition: %s", condition.Type()))
 	}
+	if field, ok := multiValuedField(condition); ok {
+		return applyMultiValued(obj, field, AnyValue, func(obj any) (bool, error) {
+			return evaluate(obj, condition)
+		})
+	}
 	return evaluate(obj, condition)
 }
 
@@ -124,6 +130,10 @@ func applyArrayContains(obj any, condition filter.Condition) (bool, error) {
 	if err != nil {
 		return false, err
 	}
+	field = indirect(field)
+	if !field.IsValid() {
+		return false, nil
+	}
 	if field.Kind() == reflect.String {
 		return applyContains(obj, filter.Contains(containsCondition.Field, fmt.Sprintf("%s", containsCondition.Value)))
 	}
@@ -131,8 +141,11 @@ func applyArrayContains(obj any, condition filter.Condition) (bool, error) {
 		return false, fmt.Errorf("field must be of type slice/array but is of type %s", field.Kind())
 	}
 	for i := 0; i < field.Len(); i++ {
-		value := field.Index(i)
-		if value.Interface() == containsCondition.Value {
+		equal, err := valuesEqual(field.Index(i), reflect.ValueOf(containsCondition.Value))
+		if err != nil {
+			return false, err
+		}
+		if equal {
 			return true, nil
 		}
 	}
@@ -156,10 +169,17 @@ func applyContains(obj any, condition filter.Condition) (bool, error) {
 	if err != nil {
 		return false, err
 	}
+	s, err := stringValue(field)
+	if errors.Is(err, errNilValue) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
 
 	return strings.Index(
-		strings.ToLower(fmt.Sprintf("%s", field.Interface())),
-		strings.ToLower(fmt.Sprintf("%s", containsCondition.Value)),
+		strings.ToLower(s),
+		strings.ToLower(containsCondition.Value),
 	) != -1, nil
 }
 
@@ -173,7 +193,7 @@ func applyEquals(obj any, condition filter.Condition) (bool, error) {
 		return false, err
 	}
 
-	return field.Interface() == equalsCondition.Value, nil
+	return valuesEqual(field, reflect.ValueOf(equalsCondition.Value))
 }
 
 func applyNotEquals(obj any, condition filter.Condition) (bool, error) {
@@ -197,30 +217,22 @@ func applyGreaterThan(obj any, condition filter.Condition) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	value := reflect.ValueOf(gtCondition.Value)
-	if field.CanInt() && value.CanInt() {
-		return field.Int() > value.Int(), nil
-	}
-	if field.CanFloat() && value.CanFloat() {
-		return field.Float() > value.Float(), nil
-	}
-	if field.CanUint() && value.CanUint() {
-		return field.Uint() > value.Uint(), nil
-	}
-	if field.Kind() == reflect.String && field.Kind() == reflect.String {
-		return field.String() > value.String(), nil
+	c, err := compareValues(field, reflect.ValueOf(gtCondition.Value))
+	if errors.Is(err, errNilValue) {
+		return false, nil
 	}
-	if reflect.TypeOf(field.Interface()).String() == "time.Time" &&
-		reflect.TypeOf(value.Interface()).String() == "time.Time" {
-		fieldValue := field.Interface().(time.Time)
-		actualValue := value.Interface().(time.Time)
-		return fieldValue.After(actualValue), nil
+	if err != nil {
+		return false, err
 	}
-	return false, fmt.Errorf("cannot compare variables of type %s and %s",
-		field.Kind(), value.Kind())
+	return c > 0, nil
 }
 
 func applyGreaterThanOrEqual(obj any, condition filter.Condition) (bool, error) {
+	// Relative times are resolved once, so both comparisons use the same time.
+	condition, err := resolveRelativeTimes(obj, condition, currentClock())
+	if err != nil {
+		return false, err
+	}
 	gteCondition, ok := condition.(*filter.GreaterThanOrEqualCondition)
 	if !ok {
 		return false, fmt.Errorf("condition is no GreaterThanOrEqualCondition")
@@ -250,8 +262,11 @@ func applyIn(obj any, condition filter.Condition) (bool, error) {
 		return false, fmt.Errorf("field must be of type slice/array but is of type %s", valueType.Kind())
 	}
 	for i := 0; i < valueType.Len(); i++ {
-		value := valueType.Index(i)
-		if value.Interface() == field.Interface() {
+		equal, err := valuesEqual(field, valueType.Index(i))
+		if err != nil {
+			return false, err
+		}
+		if equal {
 			return true, nil
 		}
 	}
@@ -267,30 +282,22 @@ func applyLowerThan(obj any, condition filter.Condition) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	value := reflect.ValueOf(ltCondition.Value)
-	if field.CanInt() && value.CanInt() {
-		return field.Int() < value.Int(), nil
-	}
-	if field.CanFloat() && value.CanFloat() {
-		return field.Float() < value.Float(), nil
-	}
-	if field.CanUint() && value.CanUint() {
-		return field.Uint() < value.Uint(), nil
-	}
-	if field.Kind() == reflect.String && field.Kind() == reflect.String {
-		return field.String() < value.String(), nil
+	c, err := compareValues(field, reflect.ValueOf(ltCondition.Value))
+	if errors.Is(err, errNilValue) {
+		return false, nil
 	}
-	if reflect.TypeOf(field.Interface()).String() == "time.Time" &&
-		reflect.TypeOf(value.Interface()).String() == "time.Time" {
-		fieldValue := field.Interface().(time.Time)
-		actualValue := value.Interface().(time.Time)
-		return fieldValue.Before(actualValue), nil
+	if err != nil {
+		return false, err
 	}
-	return false, fmt.Errorf("cannot compare variables of type %s and %s",
-		field.Kind(), value.Kind())
+	return c < 0, nil
 }
 
 func applyLowerThanOrEqual(obj any, condition filter.Condition) (bool, error) {
+	// Relative times are resolved once, so both comparisons use the same time.
+	condition, err := resolveRelativeTimes(obj, condition, currentClock())
+	if err != nil {
+		return false, err
+	}
 	lteCondition, ok := condition.(*filter.LowerThanOrEqualCondition)
 	if !ok {
 		return false, fmt.Errorf("condition is no LowerThanOrEqualCondition")
@@ -361,6 +368,10 @@ func applyArraysOverlap(obj any, condition filter.Condition) (bool, error) {
 	if err != nil {
 		return false, err
 	}
+	field = indirect(field)
+	if !field.IsValid() {
+		return false, nil
+	}
 	if field.Kind() != reflect.Slice && field.Kind() != reflect.Array {
 		return false, fmt.Errorf("field must be of type slice/array but is of type %s", field.Kind())
 	}
@@ -379,20 +390,14 @@ func applyArraysOverlap(obj any, condition filter.Condition) (bool, error) {
 		return false, nil
 	}
 
-	fieldElemType := field.Type().Elem()
-	valueElemType := v.Type().Elem()
-	if fieldElemType != valueElemType {
-		return false, fmt.Errorf("type mismatch: cannot compare %s (field) and %s (value)", fieldElemType.String(), valueElemType.String())
-	}
-
-	valueMap := make(map[any]struct{}, v.Len())
-	for i := 0; i < v.Len(); i++ {
-		valueMap[v.Index(i).Interface()] = struct{}{}
+	values, err := elementSet(field, v)
+	if err != nil {
+		return false, err
 	}
-
 	for i := 0; i < field.Len(); i++ {
-		if _, found := valueMap[field.Index(i).Interface()]; found {
-			return true, nil
+		found, err := values.contains(field.Index(i))
+		if err != nil || found {
+			return found, err
 		}
 	}
 	return false, nil
@@ -406,33 +411,386 @@ func applyOverlaps(obj any, condition filter.Condition) (bool, error) {
 	return applyArraysOverlap(obj, filter.ArraysOverlap(c.Field, c.Value))
 }
 
+// getField returns the field of the object with the name. Fields of nested structs are
+// addressed by paths like "childObject.name". Objects may also be maps with string keys,
+// whose entries are addressed like fields; missing entries are nil. Elements of slices and
+// arrays are addressed by indexes like "items[0]" or "items[-1]"; missing elements are nil.
+// If a pointer or interface on the path is nil, a nil value of the field type is returned.
+// Fields with wildcard segments have multiple values, which are returned by getFieldValues.
 func getField(obj any, name string) (reflect.Value, error) {
+	if bound, ok := obj.(*boundField); ok {
+		if name == bound.name {
+			return bound.value, nil
+		}
+		obj = bound.obj
+	}
+	v, err := rootValue(obj)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	segments, err := fieldPath(name)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if hasWildcard(segments) {
+		return reflect.Value{}, fmt.Errorf("field '%s' has multiple values", name)
+	}
+	var field reflect.Value
+	err = resolveField(v, segments, name, func(value reflect.Value) {
+		field = value
+	})
+	return field, err
+}
+
+// getFieldValues returns the values of the field of the object with the name. Wildcard
+// segments like in "addresses[*].country" address all elements of slices, arrays and maps.
+// If a pointer or interface before a wildcard segment is nil, there are no values.
+func getFieldValues(obj any, name string) ([]reflect.Value, error) {
+	if bound, ok := obj.(*boundField); ok {
+		obj = bound.obj
+	}
+	v, err := rootValue(obj)
+	if err != nil {
+		return nil, err
+	}
+	segments, err := fieldPath(name)
+	if err != nil {
+		return nil, err
+	}
+	var values []reflect.Value
+	err = resolveField(v, segments, name, func(value reflect.Value) {
+		values = append(values, value)
+	})
+	return values, err
+}
+
+func rootValue(obj any) (reflect.Value, error) {
 	var v reflect.Value
 	kind := reflect.ValueOf(obj).Kind()
 	switch kind {
 	case reflect.Ptr:
 		v = reflect.ValueOf(obj).Elem()
-	case reflect.Struct:
+	case reflect.Struct, reflect.Map:
 		v = reflect.ValueOf(obj)
 	default:
 		break
 	}
-	if !v.IsValid() {
+	if !v.IsValid() || (v.Kind() != reflect.Struct && !isStringMap(v.Type())) {
 		return reflect.Value{}, fmt.Errorf("invalid object type: %s", kind)
 	}
+	return v, nil
+}
 
-	var field reflect.Value
+// resolveField calls visit with the values at the path below v.
+func resolveField(v reflect.Value, segments []pathSegment, name string, visit func(reflect.Value)) error {
+	for i, segment := range segments {
+		for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				field, err := nilField(v.Type(), segments[i:], name)
+				if err == nil && !hasWildcard(segments[i:]) {
+					visit(field)
+				}
+				return err
+			}
+			v = v.Elem()
+		}
+		switch {
+		case segment.wildcard:
+			return resolveElements(v, segments[i+1:], name, visit)
+		case segment.indexed:
+			if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+				return fmt.Errorf("field '%s' cannot be indexed since it is of type %s", name, v.Type())
+			}
+			index := segment.index
+			if index < 0 {
+				index += v.Len()
+			}
+			if index < 0 || index >= v.Len() {
+				v = nilValue(v.Type().Elem())
+				continue
+			}
+			v = v.Index(index)
+		case isStringMap(v.Type()):
+			v = mapEntry(v, segment.name, segment.quoted)
+		default:
+			v = structField(v, segment.name)
+			if !v.IsValid() {
+				return fmt.Errorf("field '%s' was not found on object", name)
+			}
+		}
+	}
+	visit(v)
+	return nil
+}
+
+// resolveElements resolves the path below every element of the slice, array or map v.
+func resolveElements(v reflect.Value, segments []pathSegment, name string, visit func(reflect.Value)) error {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if v.Len() == 0 {
+			_, err := pathType(v.Type().Elem(), segments, name)
+			return err
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := resolveField(v.Index(i), segments, name, visit); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		if v.Len() == 0 {
+			_, err := pathType(v.Type().Elem(), segments, name)
+			return err
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			if err := resolveField(iter.Value(), segments, name, visit); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("field '%s' cannot be indexed since it is of type %s", name, v.Type())
+}
+
+// pathSegment is a segment of a field path.
+type pathSegment struct {
+	// name is the name of a struct field or the key of a map entry.
+	name string
+	// quoted is set for map keys in brackets, which only address entries with exactly that key.
+	quoted bool
+	// index is the index of a slice or array element. Negative indexes count from the end.
+	index    int
+	indexed  bool
+	wildcard bool
+}
+
+// key returns the name of the segment or, for elements, the index.
+func (s pathSegment) key() string {
+	if s.indexed {
+		return strconv.Itoa(s.index)
+	}
+	return s.name
+}
+
+// fieldPath splits the field name into its segments. Segments are separated by dots or are
+// in brackets, like the quoted map key in `labels["team-a"]`, which may contain any character,
+// the index in "items[0]" or the wildcard in "items[*]".
+func fieldPath(name string) ([]pathSegment, error) {
+	if !strings.ContainsAny(name, ".[") {
+		return []pathSegment{{name: name}}, nil
+	}
+	if !strings.Contains(name, "[") {
+		names := strings.Split(name, ".")
+		segments := make([]pathSegment, len(names))
+		for i, n := range names {
+			segments[i].name = n
+		}
+		return segments, nil
+	}
+	var segments []pathSegment
+	rest := name
+	for {
+		n := strings.IndexAny(rest, ".[")
+		if n < 0 {
+			return append(segments, pathSegment{name: rest}), nil
+		}
+		if rest[n] == '.' || n > 0 {
+			segments = append(segments, pathSegment{name: rest[:n]})
+		}
+		if rest[n] == '.' {
+			rest = rest[n+1:]
+			if strings.HasPrefix(rest, "[") {
+				return nil, fmt.Errorf("invalid field path '%s'", name)
+			}
+			continue
+		}
+		rest = rest[n:]
+		bracket := segmentPattern.FindString(rest)
+		if bracket == "" || (len(segments) == 0 && n == 0) {
+			return nil, fmt.Errorf("invalid field path '%s'", name)
+		}
+		segment, err := bracketSegment(bracket[1 : len(bracket)-1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid field path '%s'", name)
+		}
+		segments = append(segments, segment)
+		rest = rest[len(bracket):]
+		switch {
+		case rest == "":
+			return segments, nil
+		case rest[0] == '.':
+			rest = rest[1:]
+		case rest[0] != '[':
+			return nil, fmt.Errorf("invalid field path '%s'", name)
+		}
+	}
+}
+
+func bracketSegment(s string) (pathSegment, error) {
+	switch s[0] {
+	case '*':
+		return pathSegment{wildcard: true}, nil
+	case '"':
+		key, err := strconv.Unquote(s)
+		return pathSegment{name: key, quoted: true}, err
+	}
+	index, err := strconv.Atoi(s)
+	return pathSegment{index: index, indexed: true}, err
+}
+
+func hasWildcard(segments []pathSegment) bool {
+	for _, segment := range segments {
+		if segment.wildcard {
+			return true
+		}
+	}
+	return false
+}
+
+func isStringMap(t reflect.Type) bool {
+	return t.Kind() == reflect.Map && t.Key().Kind() == reflect.String
+}
+
+// mapEntry returns the entry of the map with the key or, if there is none and the key is not
+// exact, with the key matching the camel case key. Values of interfaces are unwrapped. If there
+// is no entry, a nil value of the element type is returned.
+func mapEntry(v reflect.Value, key string, exact bool) reflect.Value {
+	entry := v.MapIndex(reflect.ValueOf(key).Convert(v.Type().Key()))
+	if !entry.IsValid() && !exact {
+		entry = camelMapEntry(v, strcase.ToCamel(key))
+	}
+	if !entry.IsValid() {
+		return nilValue(v.Type().Elem())
+	}
+	if entry.Kind() == reflect.Interface && !entry.IsNil() {
+		return entry.Elem()
+	}
+	return entry
+}
+
+// camelMapEntry returns the entry of the map whose key in camel case is the camel case key.
+// Since camel case keys consist of the letters and digits of the key, only keys with as many
+// letters and digits are converted.
+func camelMapEntry(v reflect.Value, camelKey string) reflect.Value {
+	iter := v.MapRange()
+	for iter.Next() {
+		key := iter.Key().String()
+		if alphanumericCount(key) == len(camelKey) && strcase.ToCamel(key) == camelKey {
+			return iter.Value()
+		}
+	}
+	return reflect.Value{}
+}
+
+// alphanumericCount returns the number of ASCII letters and digits in s.
+func alphanumericCount(s string) int {
+	count := 0
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' {
+			count++
+		}
+	}
+	return count
+}
+
+// structField returns the field of the struct whose name matches the camel case name or,
+// if there is none, whose name in its json tag is the name.
+func structField(v reflect.Value, name string) reflect.Value {
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}
+	}
+	key := structFieldKey{t: v.Type(), name: name}
+	if index, ok := structFieldIndexes.Load(key); ok {
+		if index.(int) < 0 {
+			return reflect.Value{}
+		}
+		return v.Field(index.(int))
+	}
+	index := structFieldIndex(key.t, name)
+	structFieldIndexes.Store(key, index)
+	if index < 0 {
+		return reflect.Value{}
+	}
+	return v.Field(index)
+}
+
+// structFieldKey identifies the field with the name on a struct type.
+type structFieldKey struct {
+	t    reflect.Type
+	name string
+}
+
+// structFieldIndexes caches the indexes of struct fields by their structFieldKey.
+var structFieldIndexes sync.Map
+
+// structFieldIndex returns the index of the field with the name on the struct type t or -1
+// if it has no such field.
+func structFieldIndex(t reflect.Type, name string) int {
 	fieldName := strcase.ToCamel(name)
-	for i := 0; i < v.NumField(); i++ {
-		if strcase.ToCamel(v.Type().Field(i).Name) == fieldName {
-			field = v.Field(i)
-			break
+	for i := 0; i < t.NumField(); i++ {
+		if strcase.ToCamel(t.Field(i).Name) == fieldName {
+			return i
 		}
 	}
-	if !field.IsValid() {
-		return field, fmt.Errorf("field '%s' was not found on object", name)
+	for i := 0; i < t.NumField(); i++ {
+		tag := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if tag != "" && tag != "-" && tag == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// nilField returns a nil pointer to the type of the field at the remaining path below the
+// nil value of type t.
+func nilField(t reflect.Type, segments []pathSegment, name string) (reflect.Value, error) {
+	t, err := pathType(t, segments, name)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if t.Kind() == reflect.Interface {
+		return reflect.Zero(t), nil
+	}
+	return nilValue(t), nil
+}
+
+// pathType returns the type of the field at the path below values of type t. The type of
+// fields below interfaces is unknown, so the interface type is returned for them.
+func pathType(t reflect.Type, segments []pathSegment, name string) (reflect.Type, error) {
+	for _, segment := range segments {
+		if t.Kind() == reflect.Interface {
+			return t, nil
+		}
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		switch {
+		case segment.indexed || segment.wildcard:
+			if t.Kind() != reflect.Slice && t.Kind() != reflect.Array && !(segment.wildcard && t.Kind() == reflect.Map) {
+				return nil, fmt.Errorf("field '%s' cannot be indexed since it is of type %s", name, t)
+			}
+			t = t.Elem()
+		case isStringMap(t):
+			t = t.Elem()
+		default:
+			v := structField(reflect.Zero(t), segment.name)
+			if !v.IsValid() {
+				return nil, fmt.Errorf("field '%s' was not found on object", name)
+			}
+			t = v.Type()
+		}
+	}
+	return t, nil
+}
+
+// nilValue returns a nil value of the type or, if the type cannot be nil, a nil pointer to the type.
+func nilValue(t reflect.Type) reflect.Value {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		return reflect.Zero(t)
 	}
-	return field, nil
+	return reflect.Zero(reflect.PtrTo(t))
 }
 
 func applyArrayIsContained(obj any, condition filter.Condition) (bool, error) {
@@ -444,6 +802,10 @@ func applyArrayIsContained(obj any, condition filter.Condition) (bool, error) {
 	if err != nil {
 		return false, err
 	}
+	field = indirect(field)
+	if !field.IsValid() {
+		return false, nil
+	}
 	if field.Kind() != reflect.Slice && field.Kind() != reflect.Array {
 		return false, fmt.Errorf("field must be of type slice/array but is of type %s", field.Kind())
 	}
@@ -462,25 +824,31 @@ func applyArrayIsContained(obj any, condition filter.Condition) (bool, error) {
 		return false, nil
 	}
 
-	fieldElemType := field.Type().Elem()
-	valueElemType := v.Type().Elem()
-	if fieldElemType != valueElemType {
-		return false, fmt.Errorf("type mismatch: cannot compare %s (field) and %s (value)", fieldElemType.String(), valueElemType.String())
-	}
-
-	valueMap := make(map[any]struct{}, v.Len())
-	for i := 0; i < v.Len(); i++ {
-		valueMap[v.Index(i).Interface()] = struct{}{}
+	values, err := elementSet(field, v)
+	if err != nil {
+		return false, err
 	}
-
 	for i := 0; i < field.Len(); i++ {
-		if _, found := valueMap[field.Index(i).Interface()]; !found {
-			return false, nil
+		found, err := values.contains(field.Index(i))
+		if err != nil || !found {
+			return false, err
 		}
 	}
 	return true, nil
 }
 
+// elementSet returns the set of the elements of the value compared with the elements of the
+// field. Elements are compared like values of Equals conditions, so elements of different but
+// compatible types, e.g. int and int64 or int and any, are compared by their values.
+func elementSet(field, value reflect.Value) (*valueSet, error) {
+	fieldElemType := field.Type().Elem()
+	valueElemType := value.Type().Elem()
+	if !typesComparable(fieldElemType, valueElemType) {
+		return nil, fmt.Errorf("type mismatch: cannot compare %s (field) and %s (value)", fieldElemType.String(), valueElemType.String())
+	}
+	return newValueSet(value, fieldElemType), nil
+}
+
 func applyRegex(obj any, condition filter.Condition) (bool, error) {
 	regexCondition, ok := condition.(*filter.RegexCondition)
 	if !ok {
@@ -490,11 +858,14 @@ func applyRegex(obj any, condition filter.Condition) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-
-	if field.Kind() == reflect.Ptr {
-		field = field.Elem()
+	s, err := stringValue(field)
+	if errors.Is(err, errNilValue) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
 	}
-	return regexp.MatchString(regexCondition.Expression, field.String())
+	return regexp.MatchString(regexCondition.Expression, s)
 }
 
 func applyNotRegex(obj any, condition filter.Condition) (bool, error) {
@@ -24,7 +24,19 @@ func TestImplementsAllConditionTypes(t *testing.T) {
 		actual = append(actual, t)
 	}
 	sort.Strings(actual)
-	expected := filter.AllConditionTypes()
+	expected := append(filter.AllConditionTypes(),
+		LengthEqualsConditionType,
+		LengthGreaterThanConditionType,
+		LengthLowerThanConditionType,
+		IsEmptyConditionType,
+		NotEmptyConditionType,
+		HasKeyConditionType,
+		HasAnyKeyConditionType,
+		HasAllKeysConditionType,
+		StartsWithConditionType,
+		EndsWithConditionType,
+		ContainsTextConditionType,
+	)
 	sort.Strings(expected)
 	require.Equal(t, expected, actual)
 }
@@ -846,6 +858,46 @@ func TestApplyArrayIsContained(t *testing.T) {
 	require.False(t, applies)
 }
 
+func TestApplyArraysMixedElementTypes(t *testing.T) {
+	two, four := 2, 4
+	obj := TestObject{
+		Nicknames: []string{"foo", "bar"},
+		HouseIds:  []int{2, 4},
+	}
+	tests := []struct {
+		condition filter.Condition
+		expected  bool
+	}{
+		{condition: filter.ArraysOverlap("houseIds", []int64{4, 6}), expected: true},
+		{condition: filter.ArraysOverlap("houseIds", []any{1.0, 2.0}), expected: true},
+		{condition: filter.ArraysOverlap("houseIds", []float64{2.5}), expected: false},
+		{condition: filter.ArraysOverlap("houseIds", []*int{nil, &four}), expected: true},
+		{condition: filter.ArraysOverlap("nicknames", []State{"bar"}), expected: true},
+		{condition: filter.ArraysOverlap("nicknames", []any{1, "foo"}), expected: true},
+		{condition: filter.ArrayIsContained("houseIds", []uint8{1, 2, 4}), expected: true},
+		{condition: filter.ArrayIsContained("houseIds", []any{2, 4.0}), expected: true},
+		{condition: filter.ArrayIsContained("houseIds", []any{2, "4"}), expected: false},
+		{condition: filter.ArrayIsContained("houseIds", []*int{&two, &four}), expected: true},
+		{condition: filter.ArrayIsContained("nicknames", []State{"foo", "bar"}), expected: true},
+		{condition: filter.Overlaps("houseIds", []uint{4}), expected: true},
+	}
+	for _, test := range tests {
+		t.Run(test.condition.String(), func(t *testing.T) {
+			applies, err := FilterApplies(obj, test.condition)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, applies)
+		})
+	}
+
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	applies, err := FilterApplies(map[string]any{"dates": []time.Time{createdAt}}, filter.ArraysOverlap("dates", []string{"2024-01-02T03:04:05Z"}))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	_, err = FilterApplies(obj, filter.ArraysOverlap("houseIds", []bool{true}))
+	require.EqualError(t, err, "type mismatch: cannot compare int (field) and bool (value)")
+}
+
 func TestApplyOverlaps(t *testing.T) {
 	var applies bool
 	var err error
@@ -946,3 +998,65 @@ func TestApplyOverlaps(t *testing.T) {
 	require.Error(t, err)
 	require.False(t, applies)
 }
+
+func TestGetFieldPath(t *testing.T) {
+	obj := TestObject{
+		Id:          1,
+		ChildObject: &TestObject{Id: 2, Name: "child"},
+	}
+	applies, err := applyEquals(obj, filter.Equals("childObject.name", "child"))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyGreaterThan(&obj, filter.GreaterThan("childObject.id", 1))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyIsNil(obj, filter.IsNil("childObject.childObject.name"))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyEquals(obj, filter.Equals("childObject.childObject.id", 0))
+	require.NoError(t, err)
+	require.False(t, applies)
+
+	_, err = applyEquals(obj, filter.Equals("childObject.unknownField", 1))
+	require.EqualError(t, err, "field 'childObject.unknownField' was not found on object")
+
+	_, err = applyEquals(obj, filter.Equals("childObject.childObject.unknownField", 1))
+	require.EqualError(t, err, "field 'childObject.childObject.unknownField' was not found on object")
+
+	_, err = applyEquals(obj, filter.Equals("name.length", 1))
+	require.EqualError(t, err, "field 'name.length' was not found on object")
+}
+
+func TestFieldPath(t *testing.T) {
+	for name, expected := range map[string][]pathSegment{
+		"labels":                  {{name: "labels"}},
+		"labels.env":              {{name: "labels"}, {name: "env"}},
+		`labels["team-a"]`:        {{name: "labels"}, {name: "team-a", quoted: true}},
+		`labels["a.b"]["c\"d"].e`: {{name: "labels"}, {name: "a.b", quoted: true}, {name: `c"d`, quoted: true}, {name: "e"}},
+		`meta.labels["x"].y["z"]`: {{name: "meta"}, {name: "labels"}, {name: "x", quoted: true}, {name: "y"}, {name: "z", quoted: true}},
+		"items[0].sku":            {{name: "items"}, {index: 0, indexed: true}, {name: "sku"}},
+		"items[-1][*].sku":        {{name: "items"}, {index: -1, indexed: true}, {wildcard: true}, {name: "sku"}},
+	} {
+		segments, err := fieldPath(name)
+		require.NoError(t, err, name)
+		require.Equal(t, expected, segments, name)
+	}
+	for _, name := range []string{`["a"]`, `a[b]`, `a["b"`, `a.["b"]`, `a["b"]c`, `a[1.5]`, `a[]`} {
+		_, err := fieldPath(name)
+		require.EqualError(t, err, "invalid field path '"+name+"'")
+	}
+}
+
+func BenchmarkFilterApplies(b *testing.B) {
+	obj := TestObject{Id: 2, TaskType: "magic"}
+	condition := filter.And(filter.Equals("taskType", "magic"), filter.GreaterThan("id", 1))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := FilterApplies(obj, condition); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package filterobject
+
+import (
+	"context"
+	"fmt"
+	"github.com/xafelium/filter"
+	"sync"
+	"time"
+)
+
+// Observer is notified about the evaluation of the conditions of a CompiledCondition.
+// Conditions compiled with WithObserver and conditions evaluated with FilterAppliesObserved
+// are observed; FilterApplies, Find and Collection do not notify observers. Start is called
+// before a condition is evaluated.
+// The returned context is passed to the evaluation of the sub-conditions and to End, which
+// is called after the evaluation. This allows implementations to create nested tracing spans.
+//
+// Observers must be safe for concurrent use.
+type Observer interface {
+	Start(ctx context.Context, condition filter.Condition) context.Context
+	End(ctx context.Context, evaluation Evaluation)
+}
+
+// Evaluation is the result of the evaluation of a single condition.
+// Root is set for the evaluation of the root condition, which is evaluated once per object.
+type Evaluation struct {
+	Condition filter.Condition
+	Duration  time.Duration
+	Applies   bool
+	Err       error
+	Root      bool
+}
+
+type observedNode struct {
+	condition filter.Condition
+	child     node
+	observer  Observer
+	root      bool
+}
+
+func (n *observedNode) applies(ctx context.Context, obj any) (bool, error) {
+	ctx = n.observer.Start(ctx, n.condition)
+	start := time.Now()
+	applies, err := n.child.applies(ctx, obj)
+	n.observer.End(ctx, Evaluation{
+		Condition: n.condition,
+		Duration:  time.Since(start),
+		Applies:   applies,
+		Err:       err,
+		Root:      n.root,
+	})
+	return applies, err
+}
+
+func (n *observedNode) cost() float64 {
+	return n.child.cost()
+}
+
+func (n *observedNode) String() string {
+	return n.child.String()
+}
+
+// observeRoot marks the observed node of the root condition, so every evaluated object is
+// observed whatever the type of the root condition is. Conditions without sub-conditions,
+// which apply to all objects, are observed as an empty WhereCondition.
+func observeRoot(condition filter.Condition, root node, observer Observer) node {
+	switch n := root.(type) {
+	case nil:
+		if isNilCondition(condition) {
+			condition = filter.Where(nil)
+		}
+		return &observedNode{condition: condition, child: emptyNode{}, observer: observer, root: true}
+	case *observedNode:
+		n.root = true
+	case *memoNode:
+		n.child = observeRoot(condition, n.child, observer)
+	}
+	return root
+}
+
+// emptyNode applies to all objects.
+type emptyNode struct{}
+
+func (emptyNode) applies(context.Context, any) (bool, error) {
+	return true, nil
+}
+
+func (emptyNode) cost() float64 {
+	return 0
+}
+
+func (emptyNode) String() string {
+	return ""
+}
+
+// FilterAppliesObserved reports whether the condition applies to the object like FilterApplies
+// and notifies the observer about the evaluations like a condition compiled with WithObserver.
+// The context is passed to the observer.
+func FilterAppliesObserved(ctx context.Context, obj any, condition filter.Condition, observer Observer) (bool, error) {
+	if isEmptyCondition(condition) {
+		if isNilCondition(condition) {
+			condition = filter.Where(nil)
+		}
+		return observeApplies(ctx, condition, observer, true, func(context.Context) (bool, error) {
+			return true, nil
+		})
+	}
+	return applyObserved(ctx, obj, condition, observer, true)
+}
+
+// applyObserved evaluates the non-empty condition and its sub-conditions with the observer.
+// Empty sub-conditions apply to all objects and are not observed.
+func applyObserved(ctx context.Context, obj any, condition filter.Condition, observer Observer, root bool) (bool, error) {
+	return observeApplies(ctx, condition, observer, root, func(ctx context.Context) (bool, error) {
+		switch c := condition.(type) {
+		case *filter.WhereCondition:
+			return applyObservedChild(ctx, obj, c.Condition, observer)
+		case *filter.GroupCondition:
+			return applyObservedChild(ctx, obj, c.Condition, observer)
+		case *filter.NotCondition:
+			applies, err := applyObservedChild(ctx, obj, c.Condition, observer)
+			return !applies, err
+		case *filter.AndCondition:
+			if len(c.Conditions) < 2 {
+				return false, fmt.Errorf("AND condition must have at least two conditions")
+			}
+			for _, child := range c.Conditions {
+				if applies, err := applyObservedChild(ctx, obj, child, observer); err != nil || !applies {
+					return false, err
+				}
+			}
+			return true, nil
+		case *filter.OrCondition:
+			if len(c.Conditions) < 2 {
+				return false, fmt.Errorf("OR condition must have at least two conditions")
+			}
+			for _, child := range c.Conditions {
+				if applies, err := applyObservedChild(ctx, obj, child, observer); err != nil || applies {
+					return applies, err
+				}
+			}
+			return false, nil
+		}
+		return FilterApplies(obj, condition)
+	})
+}
+
+func applyObservedChild(ctx context.Context, obj any, condition filter.Condition, observer Observer) (bool, error) {
+	if isEmptyCondition(condition) {
+		return true, nil
+	}
+	return applyObserved(ctx, obj, condition, observer, false)
+}
+
+// isEmptyCondition reports whether the condition is nil or a WhereCondition or GroupCondition
+// without a non-empty sub-condition. Empty conditions apply to all objects.
+func isEmptyCondition(condition filter.Condition) bool {
+	switch c := condition.(type) {
+	case *filter.WhereCondition:
+		return c == nil || isEmptyCondition(c.Condition)
+	case *filter.GroupCondition:
+		return c == nil || isEmptyCondition(c.Condition)
+	}
+	return isNilCondition(condition)
+}
+
+// observeApplies notifies the observer about the evaluation of the condition by evaluate.
+func observeApplies(ctx context.Context, condition filter.Condition, observer Observer, root bool, evaluate func(context.Context) (bool, error)) (bool, error) {
+	ctx = observer.Start(ctx, condition)
+	start := time.Now()
+	applies, err := evaluate(ctx)
+	observer.End(ctx, Evaluation{
+		Condition: condition,
+		Duration:  time.Since(start),
+		Applies:   applies,
+		Err:       err,
+		Root:      root,
+	})
+	return applies, err
+}
+
+// ConditionStats contains the aggregated evaluations of a condition type.
+type ConditionStats struct {
+	Evaluations int64
+	Matches     int64
+	Errors      int64
+	Duration    time.Duration
+}
+
+// Metrics is an Observer which aggregates evaluations by condition type and counts the
+// evaluated objects.
+type Metrics struct {
+	mutex   sync.Mutex
+	stats   map[string]ConditionStats
+	objects int64
+}
+
+// NewMetrics creates a new Metrics observer.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		stats: make(map[string]ConditionStats),
+	}
+}
+
+// Start implements Observer.
+func (m *Metrics) Start(ctx context.Context, _ filter.Condition) context.Context {
+	return ctx
+}
+
+// End implements Observer.
+func (m *Metrics) End(_ context.Context, evaluation Evaluation) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	stats := m.stats[evaluation.Condition.Type()]
+	stats.Evaluations++
+	if evaluation.Applies && evaluation.Err == nil {
+		stats.Matches++
+	}
+	if evaluation.Err != nil {
+		stats.Errors++
+	}
+	stats.Duration += evaluation.Duration
+	m.stats[evaluation.Condition.Type()] = stats
+	if evaluation.Root {
+		m.objects++
+	}
+}
+
+// Objects returns the number of objects the observed conditions were evaluated for.
+func (m *Metrics) Objects() int64 {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.objects
+}
+
+// Snapshot returns the current statistics by condition type.
+func (m *Metrics) Snapshot() map[string]ConditionStats {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	snapshot := make(map[string]ConditionStats, len(m.stats))
+	for t, stats := range m.stats {
+		snapshot[t] = stats
+	}
+	return snapshot
+}
@@ -0,0 +1,162 @@
+package filterobject
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"github.com/xafelium/filter"
+	"sync"
+	"testing"
+)
+
+type depthKey struct{}
+
+type recordingObserver struct {
+	mutex   sync.Mutex
+	started []string
+	ended   []string
+}
+
+func (o *recordingObserver) Start(ctx context.Context, condition filter.Condition) context.Context {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	depth, _ := ctx.Value(depthKey{}).(int)
+	o.started = append(o.started, condition.Type())
+	return context.WithValue(ctx, depthKey{}, depth+1)
+}
+
+func (o *recordingObserver) End(ctx context.Context, evaluation Evaluation) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	depth := ctx.Value(depthKey{}).(int)
+	result := "false"
+	if evaluation.Applies {
+		result = "true"
+	}
+	if evaluation.Err != nil {
+		result = "error"
+	}
+	o.ended = append(o.ended, string(rune('0'+depth))+" "+evaluation.Condition.Type()+" "+result)
+}
+
+func TestObserver(t *testing.T) {
+	observer := &recordingObserver{}
+	compiled, err := Compile(filter.Where(filter.And(
+		filter.Equals("id", 1),
+		filter.Not(filter.Regex("name", "^a")),
+	)), WithObserver(observer))
+	require.NoError(t, err)
+
+	applies, err := compiled.Applies(TestObject{Id: 1, Name: "bob"})
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	require.Equal(t, []string{
+		filter.WhereConditionType,
+		filter.AndConditionType,
+		filter.EqualsConditionType,
+		filter.NotConditionType,
+		filter.RegexConditionType,
+	}, observer.started)
+	require.Equal(t, []string{
+		"3 EqualsCondition true",
+		"4 RegexCondition false",
+		"3 NotCondition true",
+		"2 AndCondition true",
+		"1 WhereCondition true",
+	}, observer.ended)
+}
+
+func TestObserverWithoutObserver(t *testing.T) {
+	compiled, err := Compile(filter.Where(filter.Equals("id", 1)))
+	require.NoError(t, err)
+	_, observed := compiled.root.(*observedNode)
+	require.False(t, observed)
+
+	compiled, err = Compile(filter.Where(filter.Equals("id", 1)), WithObserver(NewMetrics()))
+	require.NoError(t, err)
+	_, observed = compiled.root.(*observedNode)
+	require.True(t, observed)
+}
+
+func TestMetrics(t *testing.T) {
+	metrics := NewMetrics()
+	compiled, err := Compile(filter.Where(filter.Or(
+		filter.Equals("id", 1),
+		filter.Regex("name", "^a"),
+	)), WithObserver(metrics))
+	require.NoError(t, err)
+
+	for _, obj := range []TestObject{{Id: 1}, {Id: 2, Name: "alice"}, {Id: 3, Name: "bob"}} {
+		_, err = compiled.Applies(obj)
+		require.NoError(t, err)
+	}
+	_, err = compiled.Applies(42)
+	require.Error(t, err)
+
+	stats := metrics.Snapshot()
+	require.Equal(t, int64(4), stats[filter.WhereConditionType].Evaluations)
+	require.Equal(t, int64(2), stats[filter.WhereConditionType].Matches)
+	require.Equal(t, int64(1), stats[filter.WhereConditionType].Errors)
+	require.Equal(t, int64(4), stats[filter.EqualsConditionType].Evaluations)
+	require.Equal(t, int64(1), stats[filter.EqualsConditionType].Matches)
+	require.Equal(t, int64(2), stats[filter.RegexConditionType].Evaluations)
+	require.Equal(t, int64(1), stats[filter.RegexConditionType].Matches)
+	require.Positive(t, int64(stats[filter.WhereConditionType].Duration))
+}
+
+func TestMetricsObjects(t *testing.T) {
+	for _, condition := range []filter.Condition{
+		nil,
+		filter.Where(nil),
+		filter.Equals("id", 1),
+		filter.Where(filter.And(filter.Equals("id", 1), filter.Regex("name", "^a"))),
+	} {
+		metrics := NewMetrics()
+		compiled, err := Compile(condition, WithObserver(metrics))
+		require.NoError(t, err)
+		for _, obj := range []TestObject{{Id: 1}, {Id: 2}, {Id: 3}} {
+			_, err = compiled.Applies(obj)
+			require.NoError(t, err)
+		}
+		require.Equal(t, int64(3), metrics.Objects(), "%v", condition)
+	}
+}
+
+func TestFilterAppliesObserved(t *testing.T) {
+	obj := TestObject{Id: 1, Name: "bob"}
+	for _, condition := range []filter.Condition{
+		nil,
+		filter.Where(nil),
+		filter.Group(nil),
+		filter.Where(filter.And(filter.Equals("id", 1), filter.Not(filter.Regex("name", "^a")))),
+		filter.Where(filter.Or(filter.Equals("id", 2), filter.Group(filter.Regex("name", "^b")))),
+		filter.Or(filter.Not(nil), filter.Equals("id", 2)),
+		filter.And(filter.Where(nil), filter.Equals("id", 1)),
+	} {
+		// Sub-conditions are listed in the order of their costs, which is the order of
+		// their evaluation by compiled conditions.
+		compiledObserver := &recordingObserver{}
+		compiled, err := Compile(condition, WithObserver(compiledObserver))
+		require.NoError(t, err)
+		expected, err := compiled.Applies(obj)
+		require.NoError(t, err)
+
+		observer := &recordingObserver{}
+		applies, err := FilterAppliesObserved(context.Background(), obj, condition, observer)
+		require.NoError(t, err)
+		require.Equal(t, expected, applies, "%v", condition)
+		require.Equal(t, compiledObserver.started, observer.started, "%v", condition)
+		require.Equal(t, compiledObserver.ended, observer.ended, "%v", condition)
+	}
+
+	metrics := NewMetrics()
+	for _, obj := range []TestObject{{Id: 1}, {Id: 2}} {
+		_, err := FilterAppliesObserved(context.Background(), obj, filter.Equals("id", 1), metrics)
+		require.NoError(t, err)
+	}
+	_, err := FilterAppliesObserved(context.Background(), obj, filter.And(filter.Equals("id", 1)), metrics)
+	require.EqualError(t, err, "AND condition must have at least two conditions")
+	require.Equal(t, int64(3), metrics.Objects())
+	require.Equal(t, int64(1), metrics.Snapshot()[filter.EqualsConditionType].Matches)
+	require.Equal(t, int64(1), metrics.Snapshot()[filter.AndConditionType].Errors)
+}
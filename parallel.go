@@ -0,0 +1,123 @@
+package filterobject
+
+import (
+	"context"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+type parallelOptions struct {
+	workers   int
+	chunkSize int
+	allErrors bool
+}
+
+// ParallelOption configures FilterParallel.
+type ParallelOption func(o *parallelOptions)
+
+// WithWorkers sets the number of workers. It defaults to GOMAXPROCS.
+func WithWorkers(workers int) ParallelOption {
+	return func(o *parallelOptions) {
+		o.workers = workers
+	}
+}
+
+// WithChunkSize sets the number of objects a worker evaluates at once. It defaults to 1024.
+func WithChunkSize(size int) ParallelOption {
+	return func(o *parallelOptions) {
+		o.chunkSize = size
+	}
+}
+
+// WithAllErrors evaluates all objects and reports the errors of all objects which cannot be
+// evaluated as StreamErrors instead of stopping at the first error.
+func WithAllErrors() ParallelOption {
+	return func(o *parallelOptions) {
+		o.allErrors = true
+	}
+}
+
+// FilterParallel returns the objects to which the condition applies in their input order.
+// The objects are evaluated in chunks by a bounded pool of workers. By default, the evaluation
+// stops at the first error, which is returned as *ObjectError. If several workers fail, the
+// error of the object with the lowest index is returned.
+func FilterParallel[T any](ctx context.Context, objects []T, condition *CompiledCondition, opts ...ParallelOption) ([]T, error) {
+	options := &parallelOptions{
+		workers:   runtime.GOMAXPROCS(0),
+		chunkSize: 1024,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.workers < 1 {
+		options.workers = 1
+	}
+	if options.chunkSize < 1 {
+		options.chunkSize = 1
+	}
+	chunks := (len(objects) + options.chunkSize - 1) / options.chunkSize
+	if options.workers > chunks {
+		options.workers = chunks
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	matches := make([]bool, len(objects))
+	var next int64
+	var mutex sync.Mutex
+	var errs StreamErrors
+	var wg sync.WaitGroup
+	for w := 0; w < options.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				chunk := int(atomic.AddInt64(&next, 1) - 1)
+				if chunk >= chunks || ctx.Err() != nil {
+					return
+				}
+				end := (chunk + 1) * options.chunkSize
+				if end > len(objects) {
+					end = len(objects)
+				}
+				for i := chunk * options.chunkSize; i < end; i++ {
+					applies, err := condition.AppliesContext(ctx, objects[i])
+					if err != nil {
+						mutex.Lock()
+						errs = append(errs, &ObjectError{Index: i, Err: err})
+						mutex.Unlock()
+						if !options.allErrors {
+							cancel()
+							return
+						}
+						continue
+					}
+					matches[i] = applies
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		sort.Slice(errs, func(i, j int) bool {
+			return errs[i].Index < errs[j].Index
+		})
+		if !options.allErrors {
+			return nil, errs[0]
+		}
+		return nil, errs
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var result []T
+	for i, obj := range objects {
+		if matches[i] {
+			result = append(result, obj)
+		}
+	}
+	return result, nil
+}
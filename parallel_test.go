@@ -0,0 +1,66 @@
+package filterobject
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"github.com/xafelium/filter"
+	"testing"
+)
+
+func TestFilterParallel(t *testing.T) {
+	var objects []TestObject
+	for i := 0; i < 10000; i++ {
+		objects = append(objects, TestObject{Id: i, TaskType: []string{"a", "b", "c"}[i%3]})
+	}
+	condition, err := Compile(filter.And(filter.Equals("taskType", "b"), filter.LowerThan("id", 5000)))
+	require.NoError(t, err)
+
+	var expected []TestObject
+	for _, obj := range objects {
+		applies, err := FilterApplies(obj, condition.Condition())
+		require.NoError(t, err)
+		if applies {
+			expected = append(expected, obj)
+		}
+	}
+	for _, opts := range [][]ParallelOption{
+		nil,
+		{WithWorkers(1)},
+		{WithWorkers(8), WithChunkSize(7)},
+		{WithWorkers(100), WithChunkSize(5000), WithAllErrors()},
+	} {
+		result, err := FilterParallel(context.Background(), objects, condition, opts...)
+		require.NoError(t, err)
+		require.Equal(t, expected, result)
+	}
+
+	result, err := FilterParallel(context.Background(), []TestObject{}, condition)
+	require.NoError(t, err)
+	require.Empty(t, result)
+}
+
+func TestFilterParallelErrors(t *testing.T) {
+	objects := make([]any, 100)
+	for i := range objects {
+		objects[i] = TestObject{Id: i}
+	}
+	objects[17] = 17
+	objects[80] = "80"
+	condition, err := Compile(filter.GreaterThan("id", 1))
+	require.NoError(t, err)
+
+	_, err = FilterParallel(context.Background(), objects, condition, WithWorkers(1), WithChunkSize(10))
+	require.EqualError(t, err, "object 17: invalid object type: int")
+
+	_, err = FilterParallel(context.Background(), objects, condition, WithWorkers(4), WithChunkSize(3), WithAllErrors())
+	var errs StreamErrors
+	require.ErrorAs(t, err, &errs)
+	require.Len(t, errs, 2)
+	require.Equal(t, 17, errs[0].Index)
+	require.Equal(t, 80, errs[1].Index)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = FilterParallel(ctx, objects[:10], condition)
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,95 @@
+package filterobject
+
+import (
+	"fmt"
+	"github.com/xafelium/filter"
+	"regexp"
+)
+
+var aliasPattern = regexp.MustCompile(`^(.*\S)\s+(?i:as)\s+([A-Za-z_][A-Za-z0-9_]*)$`)
+
+// Project returns the fields of the object as a map. The fields are resolved like the fields
+// of conditions, so struct fields may also be addressed by the names of their json tags.
+// Fields are keyed by their names as given or by their alias like in "childObject.name AS
+// childName". Nested paths without an alias create nested maps, in which elements like
+// "items[0]" are keyed by their index. Fields with negative indexes require an alias.
+// Nil pointers and interfaces on a path result in nil values.
+func Project(obj any, fields ...string) (map[string]any, error) {
+	projection := make(map[string]any, len(fields))
+	for _, name := range fields {
+		var keys []string
+		if match := aliasPattern.FindStringSubmatch(name); match != nil {
+			name, keys = match[1], []string{match[2]}
+		}
+		segments, err := fieldPath(name)
+		if err != nil {
+			return nil, err
+		}
+		if keys == nil {
+			for _, segment := range segments {
+				if segment.indexed && segment.index < 0 {
+					return nil, fmt.Errorf("field '%s' cannot be projected since it has a negative index", name)
+				}
+				keys = append(keys, segment.key())
+			}
+		}
+		field, err := getField(obj, name)
+		if err != nil {
+			return nil, err
+		}
+		var value any
+		if v := indirect(field); v.IsValid() {
+			if !field.CanInterface() {
+				return nil, fmt.Errorf("field '%s' cannot be projected since it is not exported", name)
+			}
+			value = field.Interface()
+		}
+
+		target := projection
+		for _, key := range keys[:len(keys)-1] {
+			existing, found := target[key]
+			if !found {
+				nested := make(map[string]any)
+				target[key] = nested
+				target = nested
+				continue
+			}
+			nested, ok := existing.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("field '%s' overlaps with another field", name)
+			}
+			target = nested
+		}
+		last := keys[len(keys)-1]
+		if _, found := target[last]; found {
+			return nil, fmt.Errorf("field '%s' overlaps with another field", name)
+		}
+		target[last] = value
+	}
+	return projection, nil
+}
+
+// Select returns the projections of the objects to which the condition applies. The fields
+// are given like the fields of Project.
+func Select[T any](objects []T, condition filter.Condition, fields ...string) ([]map[string]any, error) {
+	compiled, err := Compile(condition)
+	if err != nil {
+		return nil, err
+	}
+	var projections []map[string]any
+	for _, obj := range objects {
+		applies, err := compiled.Applies(obj)
+		if err != nil {
+			return nil, err
+		}
+		if !applies {
+			continue
+		}
+		projection, err := Project(obj, fields...)
+		if err != nil {
+			return nil, err
+		}
+		projections = append(projections, projection)
+	}
+	return projections, nil
+}
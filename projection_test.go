@@ -0,0 +1,101 @@
+package filterobject
+
+import (
+	"github.com/stretchr/testify/require"
+	"github.com/xafelium/filter"
+	"testing"
+	"time"
+)
+
+func TestProject(t *testing.T) {
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	obj := &TestObject{
+		Id:          1,
+		Name:        "max",
+		Nicknames:   []string{"m"},
+		CreatedAt:   createdAt,
+		ChildObject: &TestObject{Id: 2, Name: "moritz"},
+	}
+	projection, err := Project(obj, "id", "nicknames", "createdAt", "childObject.name", "childObject.id", "childObject.childObject.name")
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{
+		"id":        1,
+		"nicknames": []string{"m"},
+		"createdAt": createdAt,
+		"childObject": map[string]any{
+			"name": "moritz",
+			"id":   2,
+			"childObject": map[string]any{
+				"name": nil,
+			},
+		},
+	}, projection)
+
+	projection, err = Project(TestObject{Name: "max"}, "Name", "child_object")
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"Name": "max", "child_object": nil}, projection)
+
+	projection, err = Project(map[string]any{"labels": map[string]string{"team.a": "x"}}, `labels["team.a"]`)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"labels": map[string]any{"team.a": "x"}}, projection)
+
+	projection, err = Project(obj, "childObject.name AS childName", "id as ID", `nicknames[0]`)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"childName": "moritz", "ID": 1, "nicknames": map[string]any{"0": "m"}}, projection)
+
+	projection, err = Project(map[string]any{"labels": map[string]string{"a as b": "x"}}, `labels["a as b"]`)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"labels": map[string]any{"a as b": "x"}}, projection)
+
+	_, err = Project(obj, "name AS n", "id AS n")
+	require.EqualError(t, err, "field 'id' overlaps with another field")
+
+	_, err = Project(obj, "unknownField")
+	require.EqualError(t, err, "field 'unknownField' was not found on object")
+
+	_, err = Project(obj, "childObject", "childObject.name")
+	require.EqualError(t, err, "field 'childObject.name' overlaps with another field")
+
+	_, err = Project(obj, "childObject.name", "childObject")
+	require.EqualError(t, err, "field 'childObject' overlaps with another field")
+
+	_, err = Project(struct{ secret string }{secret: "x"}, "secret")
+	require.EqualError(t, err, "field 'secret' cannot be projected since it is not exported")
+}
+
+type TaggedTestObject struct {
+	Identifier int               `json:"id"`
+	Secret     string            `json:"-"`
+	Owner      *TaggedTestObject `json:"owner_object,omitempty"`
+}
+
+func TestProjectTags(t *testing.T) {
+	obj := TaggedTestObject{Identifier: 1, Secret: "x", Owner: &TaggedTestObject{Identifier: 2}}
+	projection, err := Project(obj, "id", "owner_object.id", "identifier AS identifier")
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"id": 1, "owner_object": map[string]any{"id": 2}, "identifier": 1}, projection)
+
+	applies, err := FilterApplies(obj, filter.Equals("owner_object.id", 2))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	_, err = Project(obj, "-")
+	require.EqualError(t, err, "field '-' was not found on object")
+}
+
+func TestSelect(t *testing.T) {
+	objects := []TestObject{
+		{Id: 1, TaskType: "magic", Name: "a"},
+		{Id: 2, TaskType: "chore", Name: "b"},
+		{Id: 3, TaskType: "magic", Name: "c"},
+	}
+	projections, err := Select(objects, filter.Equals("taskType", "magic"), "id", "name")
+	require.NoError(t, err)
+	require.Equal(t, []map[string]any{
+		{"id": 1, "name": "a"},
+		{"id": 3, "name": "c"},
+	}, projections)
+
+	_, err = Select(objects, filter.Equals("unknownField", 1), "id")
+	require.Error(t, err)
+}
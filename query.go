@@ -0,0 +1,250 @@
+package filterobject
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/xafelium/filter"
+	"reflect"
+	"sort"
+)
+
+// SortKey orders objects by a field. Nil values are ordered last unless NullsFirst is set,
+// regardless of the direction.
+type SortKey struct {
+	Field      string
+	Descending bool
+	NullsFirst bool
+}
+
+// Query selects a page of the objects to which the condition applies.
+// The objects are ordered by the sort keys and then by their position in the input.
+// The page starts after the last object of the page which returned the cursor, skips
+// offset objects and contains at most limit objects if limit is greater than zero.
+type Query struct {
+	Condition filter.Condition
+	Sort      []SortKey
+	Offset    int
+	Limit     int
+	Cursor    string
+}
+
+// Page is the result of a Query.
+// Total is the number of objects to which the condition applies.
+// NextCursor continues with the objects after the page and is empty if there are none.
+type Page[T any] struct {
+	Items      []T
+	Total      int
+	NextCursor string
+}
+
+// Find returns the page of objects selected by the query.
+func Find[T any](objects []T, query Query) (Page[T], error) {
+	if query.Offset < 0 {
+		return Page[T]{}, fmt.Errorf("offset must not be negative")
+	}
+	if query.Limit < 0 {
+		return Page[T]{}, fmt.Errorf("limit must not be negative")
+	}
+	compiled, err := Compile(query.Condition)
+	if err != nil {
+		return Page[T]{}, err
+	}
+	var entries []sortEntry
+	for i, obj := range objects {
+		applies, err := compiled.Applies(obj)
+		if err != nil {
+			return Page[T]{}, err
+		}
+		if !applies {
+			continue
+		}
+		entry := sortEntry{index: i}
+		for _, key := range query.Sort {
+			value, err := getField(obj, key.Field)
+			if err != nil {
+				return Page[T]{}, err
+			}
+			entry.values = append(entry.values, indirect(value))
+		}
+		entries = append(entries, entry)
+	}
+
+	var sortErr error
+	sort.SliceStable(entries, func(i, j int) bool {
+		c, err := compareSortEntries(query.Sort, entries[i], entries[j])
+		if err != nil && sortErr == nil {
+			sortErr = err
+		}
+		return c < 0
+	})
+	if sortErr != nil {
+		return Page[T]{}, sortErr
+	}
+
+	page := Page[T]{Total: len(entries)}
+	start := 0
+	if query.Cursor != "" {
+		after, err := decodeCursor(query.Cursor, len(query.Sort))
+		if err != nil {
+			return Page[T]{}, err
+		}
+		start = len(entries)
+		skip := after.Skip
+		for i, entry := range entries {
+			c, err := after.compare(query.Sort, entry)
+			if err != nil {
+				return Page[T]{}, err
+			}
+			if c == 0 && skip > 0 {
+				skip--
+				continue
+			}
+			if c <= 0 {
+				start = i
+				break
+			}
+		}
+	}
+	start += query.Offset
+	if start > len(entries) {
+		start = len(entries)
+	}
+	end := len(entries)
+	if query.Limit > 0 && start+query.Limit < end {
+		end = start + query.Limit
+	}
+	for _, entry := range entries[start:end] {
+		page.Items = append(page.Items, objects[entry.index])
+	}
+	if end < len(entries) && end > start {
+		page.NextCursor, err = encodeCursor(query.Sort, entries[:end])
+		if err != nil {
+			return Page[T]{}, err
+		}
+	}
+	return page, nil
+}
+
+type sortEntry struct {
+	index  int
+	values []reflect.Value
+}
+
+func compareSortEntries(keys []SortKey, a, b sortEntry) (int, error) {
+	c, err := compareSortKeys(keys, a.values, b.values)
+	if err != nil || c != 0 {
+		return c, err
+	}
+	return compareOrdered(int64(a.index), int64(b.index)), nil
+}
+
+func compareSortKeys(keys []SortKey, a, b []reflect.Value) (int, error) {
+	for i, key := range keys {
+		c, err := compareSortValues(key, a[i], b[i])
+		if err != nil {
+			return 0, err
+		}
+		if c != 0 {
+			return c, nil
+		}
+	}
+	return 0, nil
+}
+
+func compareSortValues(key SortKey, a, b reflect.Value) (int, error) {
+	switch {
+	case !a.IsValid() && !b.IsValid():
+		return 0, nil
+	case !a.IsValid():
+		if key.NullsFirst {
+			return -1, nil
+		}
+		return 1, nil
+	case !b.IsValid():
+		if key.NullsFirst {
+			return 1, nil
+		}
+		return -1, nil
+	}
+	c, err := compareValues(a, b)
+	if err != nil {
+		return 0, fmt.Errorf("cannot sort by field '%s': %w", key.Field, err)
+	}
+	if key.Descending {
+		return -c, nil
+	}
+	return c, nil
+}
+
+// cursor contains the sort values of the last object of a page and the number of objects
+// with the same sort values up to this object, so the next page starts after the last object
+// even if other objects were added or removed in the meantime.
+type cursor struct {
+	Values []json.RawMessage `json:"v"`
+	Skip   int               `json:"s"`
+}
+
+// encodeCursor creates the cursor after the last of the sorted entries.
+func encodeCursor(keys []SortKey, entries []sortEntry) (string, error) {
+	last := entries[len(entries)-1]
+	c := cursor{}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if cmp, err := compareSortKeys(keys, entries[i].values, last.values); err != nil || cmp != 0 {
+			break
+		}
+		c.Skip++
+	}
+	for _, v := range last.values {
+		var value any
+		if v.IsValid() {
+			value = v.Interface()
+		}
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return "", fmt.Errorf("cannot encode cursor: %w", err)
+		}
+		c.Values = append(c.Values, raw)
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("cannot encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeCursor(s string, keys int) (*cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+	if len(c.Values) != keys {
+		return nil, fmt.Errorf("invalid cursor: expected %d sort values but got %d", keys, len(c.Values))
+	}
+	return &c, nil
+}
+
+// compare compares the sort values of the cursor with the entry. The sort values of the cursor
+// are decoded into the types of the values of the entry.
+func (c *cursor) compare(keys []SortKey, entry sortEntry) (int, error) {
+	values := make([]reflect.Value, len(keys))
+	for i, raw := range c.Values {
+		if string(raw) == "null" {
+			continue
+		}
+		t := reflect.TypeOf((*any)(nil)).Elem()
+		if entry.values[i].IsValid() {
+			t = entry.values[i].Type()
+		}
+		v := reflect.New(t)
+		if err := json.Unmarshal(raw, v.Interface()); err != nil {
+			return 0, fmt.Errorf("invalid cursor: %w", err)
+		}
+		values[i] = indirect(v)
+	}
+	return compareSortKeys(keys, values, entry.values)
+}
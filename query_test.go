@@ -0,0 +1,172 @@
+package filterobject
+
+import (
+	"github.com/stretchr/testify/require"
+	"github.com/xafelium/filter"
+	"testing"
+	"time"
+)
+
+func queryIds(objects []TestObject) []int {
+	var ids []int
+	for _, obj := range objects {
+		ids = append(ids, obj.Id)
+	}
+	return ids
+}
+
+func TestFind(t *testing.T) {
+	objects := []TestObject{
+		{Id: 1, TaskType: "b", Name: "carol"},
+		{Id: 2, TaskType: "a", Name: "dave"},
+		{Id: 3, TaskType: "b", Name: "alice"},
+		{Id: 4, TaskType: "c", Name: "bob"},
+		{Id: 5, TaskType: "a", Name: "eve"},
+	}
+	tests := []struct {
+		name     string
+		query    Query
+		expected []int
+	}{
+		{
+			name:     "input order",
+			query:    Query{},
+			expected: []int{1, 2, 3, 4, 5},
+		},
+		{
+			name:     "ascending",
+			query:    Query{Sort: []SortKey{{Field: "name"}}},
+			expected: []int{3, 4, 1, 2, 5},
+		},
+		{
+			name:     "descending with stable ties",
+			query:    Query{Sort: []SortKey{{Field: "taskType", Descending: true}}},
+			expected: []int{4, 1, 3, 2, 5},
+		},
+		{
+			name:     "multiple keys",
+			query:    Query{Sort: []SortKey{{Field: "taskType"}, {Field: "id", Descending: true}}},
+			expected: []int{5, 2, 3, 1, 4},
+		},
+		{
+			name: "condition, offset and limit",
+			query: Query{
+				Condition: filter.Not(filter.Equals("taskType", "c")),
+				Sort:      []SortKey{{Field: "name"}},
+				Offset:    1,
+				Limit:     2,
+			},
+			expected: []int{1, 2},
+		},
+		{
+			name:     "offset after end",
+			query:    Query{Offset: 10},
+			expected: nil,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			page, err := Find(objects, test.query)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, queryIds(page.Items))
+		})
+	}
+}
+
+func TestFindTotalAndCursor(t *testing.T) {
+	var objects []TestObject
+	for i := 0; i < 10; i++ {
+		objects = append(objects, TestObject{Id: i, TaskType: []string{"a", "b"}[i%2], HouseIds: []int{i}})
+	}
+	query := Query{
+		Condition: filter.Equals("taskType", "a"),
+		Sort:      []SortKey{{Field: "taskType"}, {Field: "id", Descending: true}},
+		Limit:     2,
+	}
+	var pages [][]int
+	for {
+		page, err := Find(objects, query)
+		require.NoError(t, err)
+		require.Equal(t, 5, page.Total)
+		pages = append(pages, queryIds(page.Items))
+		if page.NextCursor == "" {
+			break
+		}
+		query.Cursor = page.NextCursor
+	}
+	require.Equal(t, [][]int{{8, 6}, {4, 2}, {0}}, pages)
+
+	query.Cursor = ""
+	page, err := Find(objects, query)
+	require.NoError(t, err)
+	objects = append([]TestObject{{Id: 7, TaskType: "a"}}, objects...)
+	query.Cursor = page.NextCursor
+	page, err = Find(objects, query)
+	require.NoError(t, err)
+	require.Equal(t, []int{4, 2}, queryIds(page.Items))
+
+	query = Query{Sort: []SortKey{{Field: "taskType"}}, Limit: 3}
+	var ids []int
+	for {
+		page, err := Find(objects, query)
+		require.NoError(t, err)
+		ids = append(ids, queryIds(page.Items)...)
+		if page.NextCursor == "" {
+			break
+		}
+		query.Cursor = page.NextCursor
+	}
+	require.Equal(t, []int{7, 0, 2, 4, 6, 8, 1, 3, 5, 7, 9}, ids)
+}
+
+func TestFindNulls(t *testing.T) {
+	first := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	second := first.Add(time.Hour)
+	objects := []TimeTestObject{
+		{CreatedAt: first, DeletedAt: &second},
+		{CreatedAt: second},
+		{CreatedAt: first.Add(2 * time.Hour), DeletedAt: &first},
+	}
+	createdAt := func(page Page[TimeTestObject]) []time.Time {
+		var times []time.Time
+		for _, obj := range page.Items {
+			times = append(times, obj.CreatedAt)
+		}
+		return times
+	}
+
+	page, err := Find(objects, Query{Sort: []SortKey{{Field: "deletedAt"}}})
+	require.NoError(t, err)
+	require.Equal(t, []time.Time{objects[2].CreatedAt, first, second}, createdAt(page))
+
+	page, err = Find(objects, Query{Sort: []SortKey{{Field: "deletedAt", Descending: true, NullsFirst: true}}})
+	require.NoError(t, err)
+	require.Equal(t, []time.Time{second, first, objects[2].CreatedAt}, createdAt(page))
+
+	page, err = Find(objects, Query{Sort: []SortKey{{Field: "deletedAt", NullsFirst: true}}, Limit: 1})
+	require.NoError(t, err)
+	page, err = Find(objects, Query{Sort: []SortKey{{Field: "deletedAt", NullsFirst: true}}, Cursor: page.NextCursor})
+	require.NoError(t, err)
+	require.Equal(t, []time.Time{objects[2].CreatedAt, first}, createdAt(page))
+}
+
+func TestFindErrors(t *testing.T) {
+	objects := []TestObject{{Id: 1}, {Id: 2}}
+
+	_, err := Find(objects, Query{Sort: []SortKey{{Field: "unknownField"}}})
+	require.EqualError(t, err, "field 'unknownField' was not found on object")
+
+	_, err = Find(objects, Query{Sort: []SortKey{{Field: "houseIds"}}})
+	require.EqualError(t, err, "cannot sort by field 'houseIds': cannot compare variables of type slice and slice")
+
+	_, err = Find(objects, Query{Limit: -1})
+	require.EqualError(t, err, "limit must not be negative")
+
+	_, err = Find(objects, Query{Cursor: "not a cursor"})
+	require.Error(t, err)
+
+	page, err := Find(objects, Query{Sort: []SortKey{{Field: "id"}}, Limit: 1})
+	require.NoError(t, err)
+	_, err = Find(objects, Query{Cursor: page.NextCursor})
+	require.EqualError(t, err, "invalid cursor: expected 0 sort values but got 1")
+}
@@ -0,0 +1,318 @@
+package filterobject
+
+import (
+	"fmt"
+	"github.com/xafelium/filter"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Clock provides the current time used to resolve relative time values.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc is a function implementing Clock.
+type ClockFunc func() time.Time
+
+// Now returns the current time.
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+var relativeTimeType = reflect.TypeOf(RelativeTime{})
+
+type clockHolder struct {
+	clock Clock
+}
+
+var clock atomic.Value
+
+func init() {
+	clock.Store(clockHolder{clock: ClockFunc(time.Now)})
+}
+
+// SetClock replaces the clock used to resolve relative time values by FilterApplies and
+// by compiled conditions without a clock set with WithClock. Passing nil restores the system clock.
+func SetClock(c Clock) {
+	if c == nil {
+		c = ClockFunc(time.Now)
+	}
+	clock.Store(clockHolder{clock: c})
+}
+
+func currentClock() Clock {
+	return clock.Load().(clockHolder).clock
+}
+
+// WithClock sets the clock used to resolve the relative time values of the compiled condition.
+// By default, the clock set with SetClock is used.
+func WithClock(c Clock) CompileOption {
+	return func(o *compileOptions) {
+		o.clock = c
+	}
+}
+
+// hasRelativeTime reports whether the operand is or contains a RelativeTime or a relative
+// time string.
+func hasRelativeTime(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return false
+	case reflect.Interface:
+		return !v.IsNil() && hasRelativeTime(v.Elem())
+	case reflect.String:
+		_, err := ParseRelativeTime(v.String())
+		return err == nil
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if hasRelativeTime(v.Index(i)) {
+				return true
+			}
+		}
+		return false
+	}
+	return v.Type() == relativeTimeType
+}
+
+// resolveRelativeTimes returns the condition with the relative time values of its operand
+// resolved against the current time of the clock, so they are resolved only once when the
+// condition is evaluated. Relative time strings are only resolved if the field is a time.
+func resolveRelativeTimes(obj any, condition filter.Condition, clock Clock) (filter.Condition, error) {
+	leaf, ok := leafCoercionOf(condition)
+	if !ok || !hasRelativeTime(reflect.ValueOf(leaf.value)) {
+		return condition, nil
+	}
+	field, err := getField(obj, leaf.field)
+	if err != nil {
+		return nil, err
+	}
+	t := field.Type()
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	now := clock.Now()
+	return leaf.create(leaf.field, resolveOperand(reflect.ValueOf(leaf.value), now, t == timeType)), nil
+}
+
+func resolveOperand(v reflect.Value, now time.Time, timeField bool) any {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Interface:
+		if v.IsNil() {
+			return v.Interface()
+		}
+		return resolveOperand(v.Elem(), now, timeField)
+	case reflect.String:
+		if r, err := ParseRelativeTime(v.String()); err == nil && timeField {
+			return r.Resolve(now)
+		}
+	case reflect.Slice, reflect.Array:
+		if !hasRelativeTime(v) {
+			return v.Interface()
+		}
+		values := make([]any, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			values = append(values, resolveOperand(v.Index(i), now, timeField))
+		}
+		return values
+	}
+	if r, ok := v.Interface().(RelativeTime); ok {
+		return r.Resolve(now)
+	}
+	return v.Interface()
+}
+
+// RelativeTime is a condition value which is resolved against the current time
+// when the condition is evaluated. The offset is added to the current time first,
+// afterwards the result is truncated to the start of StartOf.
+// If Location is nil, the location of the current time is used.
+type RelativeTime struct {
+	Years    int
+	Months   int
+	Days     int
+	Duration time.Duration
+	StartOf  TimeUnit
+	Location *time.Location
+}
+
+// Now creates a RelativeTime which resolves to the current time.
+func Now() RelativeTime {
+	return RelativeTime{}
+}
+
+// Today creates a RelativeTime which resolves to the start of the current day.
+func Today() RelativeTime {
+	return RelativeTime{StartOf: Day}
+}
+
+// Resolve returns the absolute time relative to now.
+func (r RelativeTime) Resolve(now time.Time) time.Time {
+	loc := r.Location
+	if loc == nil {
+		loc = now.Location()
+	}
+	t := now.In(loc).AddDate(r.Years, r.Months, r.Days).Add(r.Duration)
+	return truncateTime(t, r.StartOf, loc)
+}
+
+// String returns the string representation of the relative time.
+func (r RelativeTime) String() string {
+	var sb strings.Builder
+	sb.WriteString("now")
+	writeOffset := func(n int, unit string) {
+		if n == 0 {
+			return
+		}
+		if n > 0 {
+			sb.WriteString("+")
+		}
+		sb.WriteString(strconv.Itoa(n) + unit)
+	}
+	writeOffset(r.Years, "y")
+	writeOffset(r.Months, "M")
+	writeOffset(r.Days, "d")
+	switch {
+	case r.Duration%time.Hour == 0:
+		writeOffset(int(r.Duration/time.Hour), "h")
+	case r.Duration%time.Minute == 0:
+		writeOffset(int(r.Duration/time.Minute), "m")
+	case r.Duration%time.Second == 0:
+		writeOffset(int(r.Duration/time.Second), "s")
+	case r.Duration%time.Millisecond == 0:
+		writeOffset(int(r.Duration/time.Millisecond), "ms")
+	case r.Duration%time.Microsecond == 0:
+		writeOffset(int(r.Duration/time.Microsecond), "us")
+	default:
+		writeOffset(int(r.Duration), "ns")
+	}
+	if r.StartOf != Nanosecond {
+		for symbol, unit := range relativeTimeUnits {
+			if unit == r.StartOf {
+				sb.WriteString("/" + symbol)
+			}
+		}
+	}
+	return sb.String()
+}
+
+var relativeTimeUnits = map[string]TimeUnit{
+	"s": Second,
+	"m": Minute,
+	"h": Hour,
+	"d": Day,
+	"w": Week,
+	"M": Month,
+	"y": Year,
+}
+
+var relativeTimeUnitNames = map[string]TimeUnit{
+	"minute": Minute,
+	"hour":   Hour,
+	"day":    Day,
+	"today":  Day,
+	"week":   Week,
+	"month":  Month,
+	"year":   Year,
+}
+
+var relativeTimeAliases = map[string]string{
+	"today":     "now/d",
+	"yesterday": "now-1d/d",
+	"tomorrow":  "now+1d/d",
+}
+
+// ParseRelativeTime parses relative time expressions. The expression starts with "now",
+// followed by any number of offsets like "-7d" or "+2h" and an optional truncation like "/d".
+// Supported units are ns, us, ms, s, m, h, d, w, M (months) and y.
+// Additionally "today", "yesterday", "tomorrow" and "start of <unit>" (minute, hour, day,
+// today, week, month, year) are supported.
+func ParseRelativeTime(expression string) (RelativeTime, error) {
+	expr := strings.TrimSpace(expression)
+	if strings.HasPrefix(expr, "start of ") {
+		name := strings.TrimPrefix(strings.TrimPrefix(expr, "start of "), "this ")
+		if alias, ok := relativeTimeAliases[name]; ok {
+			expr = alias
+		} else if unit, ok := relativeTimeUnitNames[name]; ok {
+			return RelativeTime{StartOf: unit}, nil
+		} else {
+			return RelativeTime{}, fmt.Errorf("invalid relative time %q: unknown unit %q", expression, name)
+		}
+	}
+	if alias, ok := relativeTimeAliases[expr]; ok {
+		expr = alias
+	}
+	if !strings.HasPrefix(expr, "now") {
+		return RelativeTime{}, fmt.Errorf("invalid relative time %q: must start with 'now'", expression)
+	}
+	expr = strings.TrimPrefix(expr, "now")
+
+	var r RelativeTime
+	if i := strings.Index(expr, "/"); i != -1 {
+		unit, ok := relativeTimeUnits[expr[i+1:]]
+		if !ok {
+			return RelativeTime{}, fmt.Errorf("invalid relative time %q: unknown unit %q", expression, expr[i+1:])
+		}
+		r.StartOf = unit
+		expr = expr[:i]
+	}
+	for len(expr) > 0 {
+		sign := 1
+		switch expr[0] {
+		case '+':
+		case '-':
+			sign = -1
+		default:
+			return RelativeTime{}, fmt.Errorf("invalid relative time %q: expected '+' or '-'", expression)
+		}
+		expr = expr[1:]
+		end := 0
+		for end < len(expr) && expr[end] >= '0' && expr[end] <= '9' {
+			end++
+		}
+		if end == 0 || end == len(expr) {
+			return RelativeTime{}, fmt.Errorf("invalid relative time %q: expected number and unit", expression)
+		}
+		n, err := strconv.Atoi(expr[:end])
+		if err != nil {
+			return RelativeTime{}, fmt.Errorf("invalid relative time %q: %w", expression, err)
+		}
+		n *= sign
+		unit := expr[end : end+1]
+		for _, u := range []string{"ms", "us", "ns"} {
+			if strings.HasPrefix(expr[end:], u) {
+				unit = u
+			}
+		}
+		switch unit {
+		case "ns":
+			r.Duration += time.Duration(n)
+		case "us":
+			r.Duration += time.Duration(n) * time.Microsecond
+		case "ms":
+			r.Duration += time.Duration(n) * time.Millisecond
+		case "s":
+			r.Duration += time.Duration(n) * time.Second
+		case "m":
+			r.Duration += time.Duration(n) * time.Minute
+		case "h":
+			r.Duration += time.Duration(n) * time.Hour
+		case "d":
+			r.Days += n
+		case "w":
+			r.Days += 7 * n
+		case "M":
+			r.Months += n
+		case "y":
+			r.Years += n
+		default:
+			return RelativeTime{}, fmt.Errorf("invalid relative time %q: unknown unit %q", expression, expr[end])
+		}
+		expr = expr[end+len(unit):]
+	}
+	return r, nil
+}
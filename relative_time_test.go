@@ -0,0 +1,184 @@
+package filterobject
+
+import (
+	"github.com/stretchr/testify/require"
+	"github.com/xafelium/filter"
+	"testing"
+	"time"
+)
+
+func fixedClock(t time.Time) Clock {
+	return ClockFunc(func() time.Time {
+		return t
+	})
+}
+
+func TestParseRelativeTime(t *testing.T) {
+	tests := []struct {
+		expression string
+		expected   RelativeTime
+		err        bool
+	}{
+		{expression: "now", expected: RelativeTime{}},
+		{expression: "now-7d", expected: RelativeTime{Days: -7}},
+		{expression: "now+2h", expected: RelativeTime{Duration: 2 * time.Hour}},
+		{expression: "now-1w+30m", expected: RelativeTime{Days: -7, Duration: 30 * time.Minute}},
+		{expression: "now-1M/M", expected: RelativeTime{Months: -1, StartOf: Month}},
+		{expression: "now-1y/y", expected: RelativeTime{Years: -1, StartOf: Year}},
+		{expression: "now/d", expected: RelativeTime{StartOf: Day}},
+		{expression: "today", expected: RelativeTime{StartOf: Day}},
+		{expression: "start of today", expected: RelativeTime{StartOf: Day}},
+		{expression: "yesterday", expected: RelativeTime{Days: -1, StartOf: Day}},
+		{expression: "start of tomorrow", expected: RelativeTime{Days: 1, StartOf: Day}},
+		{expression: "start of week", expected: RelativeTime{StartOf: Week}},
+		{expression: "start of this month", expected: RelativeTime{StartOf: Month}},
+		{expression: "now+1s-250ms", expected: RelativeTime{Duration: 750 * time.Millisecond}},
+		{expression: "now+3us+4ns/s", expected: RelativeTime{Duration: 3004 * time.Nanosecond, StartOf: Second}},
+		{expression: "later", err: true},
+		{expression: "now-7mx", err: true},
+		{expression: "now-7", err: true},
+		{expression: "now-d", err: true},
+		{expression: "now-7x", err: true},
+		{expression: "now*7d", err: true},
+		{expression: "now/x", err: true},
+		{expression: "start of decade", err: true},
+	}
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			test := test
+
+			actual, err := ParseRelativeTime(test.expression)
+
+			if test.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestRelativeTimeString(t *testing.T) {
+	r := RelativeTime{Days: -1, Duration: 2*time.Hour + 30*time.Second + 5*time.Millisecond}
+	parsed, err := ParseRelativeTime(r.String())
+	require.NoError(t, err)
+	require.Equal(t, r, parsed)
+
+	for _, expression := range []string{"now", "now-7d", "now+2h", "now-1y+3M-2d+90s/d", "now-30m/w", "now+1500ms", "now-2us", "now+1000000001ns"} {
+		r, err := ParseRelativeTime(expression)
+		require.NoError(t, err)
+		require.Equal(t, expression, r.String())
+	}
+}
+
+func TestRelativeTimeResolve(t *testing.T) {
+	now := time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC)
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	require.Equal(t, now, Now().Resolve(now))
+	require.Equal(t, time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), Today().Resolve(now))
+	require.Equal(t, time.Date(2024, 3, 8, 10, 30, 0, 0, time.UTC), RelativeTime{Days: -7}.Resolve(now))
+	require.Equal(t, time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC), RelativeTime{StartOf: Week}.Resolve(now))
+	require.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), RelativeTime{Months: -1, StartOf: Month}.Resolve(now))
+	require.Equal(t, time.Date(2024, 3, 15, 0, 0, 0, 0, tokyo), RelativeTime{StartOf: Day, Location: tokyo}.Resolve(now))
+}
+
+func TestRelativeTimeConditions(t *testing.T) {
+	var applies bool
+	var err error
+	SetClock(fixedClock(time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC)))
+	defer SetClock(nil)
+	obj := TimeTestObject{
+		CreatedAt: time.Date(2024, 3, 10, 8, 0, 0, 0, time.UTC),
+	}
+
+	applies, err = applyGreaterThan(obj, filter.GreaterThan("createdAt", RelativeTime{Days: -7}))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyGreaterThan(obj, filter.GreaterThan("createdAt", RelativeTime{Days: -1}))
+	require.NoError(t, err)
+	require.False(t, applies)
+
+	applies, err = applyLowerThan(obj, filter.LowerThan("createdAt", Today()))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyGreaterThanOrEqual(obj, filter.GreaterThanOrEqual("createdAt", "now-5d/d"))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyEquals(obj, filter.Equals("createdAt", "now-5d-2h-30m"))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyLowerThan(obj, filter.LowerThan("createdAt", "start of week"))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	// The value is resolved when the condition is evaluated
+	condition := filter.GreaterThan("createdAt", "now-7d")
+	applies, err = FilterApplies(obj, condition)
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	SetClock(fixedClock(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)))
+	applies, err = FilterApplies(obj, condition)
+	require.NoError(t, err)
+	require.False(t, applies)
+}
+
+func TestRelativeTimeWithClock(t *testing.T) {
+	obj := TimeTestObject{
+		CreatedAt: time.Date(2024, 3, 10, 8, 0, 0, 0, time.UTC),
+	}
+	march := fixedClock(time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC))
+	april := fixedClock(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC))
+	conditions := []filter.Condition{
+		filter.GreaterThan("createdAt", "now-7d"),
+		filter.GreaterThanOrEqual("createdAt", RelativeTime{Days: -7}),
+		filter.In("createdAt", []any{"now-5d-2h-30m"}),
+	}
+	for _, condition := range conditions {
+		inMarch, err := Compile(condition, WithClock(march))
+		require.NoError(t, err)
+		inApril, err := Compile(condition, WithClock(april))
+		require.NoError(t, err)
+
+		applies, err := inMarch.Applies(obj)
+		require.NoError(t, err)
+		require.True(t, applies, condition.String())
+		applies, err = inApril.Applies(obj)
+		require.NoError(t, err)
+		require.False(t, applies, condition.String())
+	}
+
+	compiled, err := Compile(filter.Equals("name", "today"), WithClock(march))
+	require.NoError(t, err)
+	applies, err := compiled.Applies(TestObject{Name: "today"})
+	require.NoError(t, err)
+	require.True(t, applies)
+}
+
+func TestRelativeTimeResolvedOnce(t *testing.T) {
+	calls := 0
+	now := time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC)
+	SetClock(ClockFunc(func() time.Time {
+		calls++
+		return now.Add(time.Duration(calls) * time.Nanosecond)
+	}))
+	defer SetClock(nil)
+	obj := TimeTestObject{CreatedAt: now.Add(time.Nanosecond)}
+
+	applies, err := FilterApplies(obj, filter.GreaterThanOrEqual("createdAt", Now()))
+	require.NoError(t, err)
+	require.True(t, applies)
+	require.Equal(t, 1, calls)
+
+	applies, err = FilterApplies(obj, filter.LowerThanOrEqual("createdAt", "now"))
+	require.NoError(t, err)
+	require.True(t, applies)
+	require.Equal(t, 2, calls)
+}
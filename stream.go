@@ -0,0 +1,213 @@
+package filterobject
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Seq is an iterator over objects. It has the same shape as iter.Seq, so iterators of the
+// iter package can be converted into a Seq and vice versa.
+type Seq[T any] func(yield func(T) bool)
+
+// Source is a pull-based source of objects. Next returns false if there are no more objects.
+type Source[T any] interface {
+	Next() (T, bool, error)
+}
+
+// SourceFunc is a function implementing Source.
+type SourceFunc[T any] func() (T, bool, error)
+
+// Next implements Source.
+func (f SourceFunc[T]) Next() (T, bool, error) {
+	return f()
+}
+
+// ErrorPolicy defines how errors of the evaluation of objects of a stream are handled.
+type ErrorPolicy int
+
+const (
+	// StopOnError stops the stream at the first error.
+	StopOnError ErrorPolicy = iota
+	// SkipErrors drops objects which cannot be evaluated.
+	SkipErrors
+	// CollectErrors drops objects which cannot be evaluated and reports all errors at the end of the stream.
+	CollectErrors
+)
+
+// ObjectError is the error of the evaluation of the object at the index of a stream.
+type ObjectError struct {
+	Index int
+	Err   error
+}
+
+// Error returns the error message.
+func (e *ObjectError) Error() string {
+	return fmt.Sprintf("object %d: %s", e.Index, e.Err)
+}
+
+// Unwrap returns the error of the evaluation.
+func (e *ObjectError) Unwrap() error {
+	return e.Err
+}
+
+// StreamErrors contains the errors collected with CollectErrors.
+type StreamErrors []*ObjectError
+
+// Error returns the error message.
+func (e StreamErrors) Error() string {
+	if len(e) == 1 {
+		return fmt.Sprintf("1 object could not be evaluated: %s", e[0])
+	}
+	return fmt.Sprintf("%d objects could not be evaluated, first: %s", len(e), e[0])
+}
+
+// streamState evaluates the objects of a stream and tracks its errors.
+type streamState struct {
+	condition *CompiledCondition
+	policy    ErrorPolicy
+	index     int
+	errs      StreamErrors
+	err       error
+}
+
+// evaluate reports whether the condition applies to the next object and whether the stream must stop.
+func (s *streamState) evaluate(ctx context.Context, obj any) (bool, bool) {
+	index := s.index
+	s.index++
+	applies, err := s.condition.AppliesContext(ctx, obj)
+	if err == nil {
+		return applies, false
+	}
+	objectErr := &ObjectError{Index: index, Err: err}
+	switch s.policy {
+	case SkipErrors:
+		return false, false
+	case CollectErrors:
+		s.errs = append(s.errs, objectErr)
+		return false, false
+	default:
+		s.err = objectErr
+		return false, true
+	}
+}
+
+func (s *streamState) error() error {
+	if s.err != nil {
+		return s.err
+	}
+	if len(s.errs) > 0 {
+		return s.errs
+	}
+	return nil
+}
+
+// FilterSeq lazily filters the sequence by the condition. The returned function reports the
+// errors of the last iteration of the filtered sequence according to the policy.
+func FilterSeq[T any](seq Seq[T], condition *CompiledCondition, policy ErrorPolicy) (Seq[T], func() error) {
+	var mutex sync.Mutex
+	var last *streamState
+	filtered := func(yield func(T) bool) {
+		state := &streamState{condition: condition, policy: policy}
+		defer func() {
+			mutex.Lock()
+			defer mutex.Unlock()
+			last = state
+		}()
+		seq(func(obj T) bool {
+			applies, stop := state.evaluate(context.Background(), obj)
+			if stop {
+				return false
+			}
+			if !applies {
+				return true
+			}
+			return yield(obj)
+		})
+	}
+	return filtered, func() error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if last == nil {
+			return nil
+		}
+		return last.error()
+	}
+}
+
+// FilterChan filters the objects received from the channel by the condition and sends the
+// objects to which the condition applies to the returned channel. The returned channel is closed
+// when the input channel is closed, the context is done or the stream stops due to an error.
+// The returned function waits for the returned channel to be closed and reports the errors
+// according to the policy or the error of the context.
+func FilterChan[T any](ctx context.Context, in <-chan T, condition *CompiledCondition, policy ErrorPolicy) (<-chan T, func() error) {
+	out := make(chan T)
+	done := make(chan struct{})
+	state := &streamState{condition: condition, policy: policy}
+	go func() {
+		defer close(done)
+		defer close(out)
+		for {
+			var obj T
+			var ok bool
+			select {
+			case <-ctx.Done():
+				state.err = ctx.Err()
+				return
+			case obj, ok = <-in:
+				if !ok {
+					return
+				}
+			}
+			applies, stop := state.evaluate(ctx, obj)
+			if stop {
+				return
+			}
+			if !applies {
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				state.err = ctx.Err()
+				return
+			case out <- obj:
+			}
+		}
+	}()
+	return out, func() error {
+		<-done
+		return state.error()
+	}
+}
+
+// FilterSource lazily filters the objects pulled from the source by the condition.
+// Errors of the source are always returned by Next. With StopOnError, evaluation errors are
+// returned by Next as well and end the source. The returned function reports the errors
+// according to the policy.
+func FilterSource[T any](source Source[T], condition *CompiledCondition, policy ErrorPolicy) (Source[T], func() error) {
+	state := &streamState{condition: condition, policy: policy}
+	stopped := false
+	next := func() (T, bool, error) {
+		var zero T
+		for !stopped {
+			obj, ok, err := source.Next()
+			if err != nil {
+				return zero, false, err
+			}
+			if !ok {
+				stopped = true
+				break
+			}
+			applies, stop := state.evaluate(context.Background(), obj)
+			if stop {
+				stopped = true
+				return zero, false, state.err
+			}
+			if applies {
+				return obj, true, nil
+			}
+		}
+		return zero, false, nil
+	}
+	return SourceFunc[T](next), state.error
+}
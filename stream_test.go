@@ -0,0 +1,189 @@
+package filterobject
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"github.com/xafelium/filter"
+	"testing"
+)
+
+func streamObjects() []any {
+	return []any{
+		TestObject{Id: 1},
+		TestObject{Id: 2},
+		42,
+		TestObject{Id: 3},
+		"invalid",
+		TestObject{Id: 4},
+	}
+}
+
+func sliceSeq[T any](objects []T) Seq[T] {
+	return func(yield func(T) bool) {
+		for _, obj := range objects {
+			if !yield(obj) {
+				return
+			}
+		}
+	}
+}
+
+func streamIds(objects []any) []int {
+	var ids []int
+	for _, obj := range objects {
+		ids = append(ids, obj.(TestObject).Id)
+	}
+	return ids
+}
+
+func TestFilterSeq(t *testing.T) {
+	condition, err := Compile(filter.GreaterThan("id", 1))
+	require.NoError(t, err)
+
+	tests := []struct {
+		policy   ErrorPolicy
+		expected []int
+		err      string
+	}{
+		{policy: StopOnError, expected: []int{2}, err: "object 2: invalid object type: int"},
+		{policy: SkipErrors, expected: []int{2, 3, 4}},
+		{policy: CollectErrors, expected: []int{2, 3, 4}, err: "2 objects could not be evaluated, first: object 2: invalid object type: int"},
+	}
+	for _, test := range tests {
+		seq, errs := FilterSeq(sliceSeq(streamObjects()), condition, test.policy)
+		require.NoError(t, errs())
+		var objects []any
+		seq(func(obj any) bool {
+			objects = append(objects, obj)
+			return true
+		})
+		require.Equal(t, test.expected, streamIds(objects))
+		if test.err == "" {
+			require.NoError(t, errs())
+		} else {
+			require.EqualError(t, errs(), test.err)
+		}
+	}
+
+	seq, errs := FilterSeq(sliceSeq(streamObjects()), condition, CollectErrors)
+	var objects []any
+	seq(func(obj any) bool {
+		objects = append(objects, obj)
+		return len(objects) < 2
+	})
+	require.Equal(t, []int{2, 3}, streamIds(objects))
+	var streamErrs StreamErrors
+	require.ErrorAs(t, errs(), &streamErrs)
+	require.Len(t, streamErrs, 1)
+	require.Equal(t, 2, streamErrs[0].Index)
+}
+
+func TestFilterChan(t *testing.T) {
+	condition, err := Compile(filter.GreaterThan("id", 1))
+	require.NoError(t, err)
+
+	in := make(chan any)
+	go func() {
+		defer close(in)
+		for _, obj := range streamObjects() {
+			in <- obj
+		}
+	}()
+	out, errs := FilterChan(context.Background(), in, condition, CollectErrors)
+	var objects []any
+	for obj := range out {
+		objects = append(objects, obj)
+	}
+	require.Equal(t, []int{2, 3, 4}, streamIds(objects))
+	var streamErrs StreamErrors
+	require.ErrorAs(t, errs(), &streamErrs)
+	require.Len(t, streamErrs, 2)
+
+	in = make(chan any, 10)
+	for _, obj := range streamObjects() {
+		in <- obj
+	}
+	close(in)
+	out, errs = FilterChan(context.Background(), in, condition, StopOnError)
+	objects = nil
+	for obj := range out {
+		objects = append(objects, obj)
+	}
+	require.Equal(t, []int{2}, streamIds(objects))
+	var objectErr *ObjectError
+	require.ErrorAs(t, errs(), &objectErr)
+	require.Equal(t, 2, objectErr.Index)
+}
+
+func TestFilterChanCancel(t *testing.T) {
+	condition, err := Compile(filter.GreaterThan("id", 1))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan TestObject)
+	go func() {
+		for i := 0; ; i++ {
+			select {
+			case in <- TestObject{Id: i}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	out, errs := FilterChan(ctx, in, condition, StopOnError)
+	obj := <-out
+	require.Equal(t, 2, obj.Id)
+	cancel()
+	for range out {
+	}
+	require.ErrorIs(t, errs(), context.Canceled)
+}
+
+func TestFilterSource(t *testing.T) {
+	condition, err := Compile(filter.GreaterThan("id", 1))
+	require.NoError(t, err)
+
+	pull := func(objects []any, sourceErr error) Source[any] {
+		i := 0
+		return SourceFunc[any](func() (any, bool, error) {
+			if i >= len(objects) {
+				return nil, false, sourceErr
+			}
+			i++
+			return objects[i-1], true, nil
+		})
+	}
+	collect := func(source Source[any]) ([]any, error) {
+		var objects []any
+		for {
+			obj, ok, err := source.Next()
+			if err != nil || !ok {
+				return objects, err
+			}
+			objects = append(objects, obj)
+		}
+	}
+
+	source, errs := FilterSource(pull(streamObjects(), nil), condition, SkipErrors)
+	objects, err := collect(source)
+	require.NoError(t, err)
+	require.Equal(t, []int{2, 3, 4}, streamIds(objects))
+	require.NoError(t, errs())
+
+	source, errs = FilterSource(pull(streamObjects(), nil), condition, StopOnError)
+	objects, err = collect(source)
+	require.EqualError(t, err, "object 2: invalid object type: int")
+	require.Equal(t, []int{2}, streamIds(objects))
+	require.Equal(t, err, errs())
+	_, ok, err := source.Next()
+	require.False(t, ok)
+	require.NoError(t, err)
+
+	sourceErr := errors.New("connection lost")
+	source, errs = FilterSource(pull(streamObjects(), sourceErr), condition, CollectErrors)
+	objects, err = collect(source)
+	require.ErrorIs(t, err, sourceErr)
+	require.Equal(t, []int{2, 3, 4}, streamIds(objects))
+	require.Error(t, errs())
+}
@@ -0,0 +1,35 @@
+package filterobject
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+)
+
+// stringValue returns the string representation of v. Values which are not of kind string
+// must implement encoding.TextMarshaler or fmt.Stringer.
+func stringValue(v reflect.Value) (string, error) {
+	v = indirect(v)
+	if !v.IsValid() {
+		return "", errNilValue
+	}
+	if v.Kind() == reflect.String {
+		return v.String(), nil
+	}
+	receivers := methodReceivers(v)
+	for _, receiver := range receivers {
+		if m, ok := receiver.Interface().(encoding.TextMarshaler); ok {
+			text, err := m.MarshalText()
+			if err != nil {
+				return "", fmt.Errorf("cannot convert value of type %s into a string: %w", v.Type(), err)
+			}
+			return string(text), nil
+		}
+	}
+	for _, receiver := range receivers {
+		if s, ok := receiver.Interface().(fmt.Stringer); ok {
+			return s.String(), nil
+		}
+	}
+	return "", fmt.Errorf("cannot convert value of type %s into a string", v.Type())
+}
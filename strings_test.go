@@ -0,0 +1,102 @@
+package filterobject
+
+import (
+	"github.com/stretchr/testify/require"
+	"github.com/xafelium/filter"
+	"net"
+	"testing"
+)
+
+type Status int
+
+const (
+	StatusActive Status = iota + 1
+	StatusArchived
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusActive:
+		return "Active"
+	case StatusArchived:
+		return "Archived"
+	default:
+		return "Unknown"
+	}
+}
+
+type StringTestObject struct {
+	Status   Status
+	Previous *Status
+	Address  net.IP
+	Count    int
+	Tags     []string
+}
+
+func TestStringValueConditions(t *testing.T) {
+	var applies bool
+	var err error
+	obj := StringTestObject{
+		Status:  StatusActive,
+		Address: net.ParseIP("192.168.0.10"),
+		Count:   5,
+	}
+
+	// Stringer
+	applies, err = applyContains(obj, filter.Contains("status", "act"))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyRegex(obj, filter.Regex("status", "^Active$"))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyNotRegex(obj, filter.NotRegex("status", "^Archived$"))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyEquals(obj, filter.Equals("status", "Active"))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyEquals(obj, filter.Equals("status", "Archived"))
+	require.NoError(t, err)
+	require.False(t, applies)
+
+	applies, err = applyEquals(obj, filter.Equals("status", StatusActive))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	// TextMarshaler
+	applies, err = applyRegex(obj, filter.Regex("address", `^192\.168\.`))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyEquals(obj, filter.Equals("address", "192.168.0.10"))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	// Nil values
+	applies, err = applyContains(obj, filter.Contains("previous", "act"))
+	require.NoError(t, err)
+	require.False(t, applies)
+
+	previous := StatusArchived
+	obj.Previous = &previous
+	applies, err = applyContains(obj, filter.Contains("previous", "archived"))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	// Errors
+	applies, err = applyContains(obj, filter.Contains("count", "5"))
+	require.EqualError(t, err, "cannot convert value of type int into a string")
+	require.False(t, applies)
+
+	applies, err = applyRegex(obj, filter.Regex("tags", "foo"))
+	require.EqualError(t, err, "cannot convert value of type []string into a string")
+	require.False(t, applies)
+
+	applies, err = applyNotRegex(obj, filter.NotRegex("count", "5"))
+	require.Error(t, err)
+	require.False(t, applies)
+}
@@ -0,0 +1,196 @@
+package filterobject
+
+import (
+	"errors"
+	"fmt"
+	"github.com/xafelium/filter"
+	"reflect"
+	"strings"
+)
+
+const (
+	StartsWithConditionType   = "StartsWithCondition"
+	EndsWithConditionType     = "EndsWithCondition"
+	ContainsTextConditionType = "ContainsTextCondition"
+)
+
+func init() {
+	conditionEvaluators[StartsWithConditionType] = applyStartsWith
+	conditionEvaluators[EndsWithConditionType] = applyEndsWith
+	conditionEvaluators[ContainsTextConditionType] = applyContainsText
+}
+
+// StartsWithCondition filters strings which start with the value. Fields which are slices
+// or arrays apply if any of their elements starts with the value.
+type StartsWithCondition struct {
+	Field      string
+	Value      string
+	IgnoreCase bool
+}
+
+// StartsWith creates a new StartsWithCondition which compares case-sensitively.
+func StartsWith(field string, value string) filter.Condition {
+	return &StartsWithCondition{
+		Field: field,
+		Value: value,
+	}
+}
+
+// StartsWithIgnoreCase creates a new StartsWithCondition which compares case-insensitively.
+func StartsWithIgnoreCase(field string, value string) filter.Condition {
+	return &StartsWithCondition{
+		Field:      field,
+		Value:      value,
+		IgnoreCase: true,
+	}
+}
+
+// String returns the string representation of the condition.
+func (c *StartsWithCondition) String() string {
+	return fmt.Sprintf("%s %sSTARTS WITH %q", c.Field, ignoreCasePrefix(c.IgnoreCase), c.Value)
+}
+
+// Type returns the name of the condition.
+func (c *StartsWithCondition) Type() string {
+	return StartsWithConditionType
+}
+
+// EndsWithCondition filters strings which end with the value. Fields which are slices
+// or arrays apply if any of their elements ends with the value.
+type EndsWithCondition struct {
+	Field      string
+	Value      string
+	IgnoreCase bool
+}
+
+// EndsWith creates a new EndsWithCondition which compares case-sensitively.
+func EndsWith(field string, value string) filter.Condition {
+	return &EndsWithCondition{
+		Field: field,
+		Value: value,
+	}
+}
+
+// EndsWithIgnoreCase creates a new EndsWithCondition which compares case-insensitively.
+func EndsWithIgnoreCase(field string, value string) filter.Condition {
+	return &EndsWithCondition{
+		Field:      field,
+		Value:      value,
+		IgnoreCase: true,
+	}
+}
+
+// String returns the string representation of the condition.
+func (c *EndsWithCondition) String() string {
+	return fmt.Sprintf("%s %sENDS WITH %q", c.Field, ignoreCasePrefix(c.IgnoreCase), c.Value)
+}
+
+// Type returns the name of the condition.
+func (c *EndsWithCondition) Type() string {
+	return EndsWithConditionType
+}
+
+// ContainsTextCondition filters strings which contain the value. Unlike filter.ContainsCondition,
+// it compares case-sensitively unless IgnoreCase is set, and fields which are slices or arrays
+// apply if any of their elements contains the value.
+type ContainsTextCondition struct {
+	Field      string
+	Value      string
+	IgnoreCase bool
+}
+
+// ContainsText creates a new ContainsTextCondition which compares case-sensitively.
+func ContainsText(field string, value string) filter.Condition {
+	return &ContainsTextCondition{
+		Field: field,
+		Value: value,
+	}
+}
+
+// ContainsTextIgnoreCase creates a new ContainsTextCondition which compares case-insensitively.
+func ContainsTextIgnoreCase(field string, value string) filter.Condition {
+	return &ContainsTextCondition{
+		Field:      field,
+		Value:      value,
+		IgnoreCase: true,
+	}
+}
+
+// String returns the string representation of the condition.
+func (c *ContainsTextCondition) String() string {
+	return fmt.Sprintf("%s %sCONTAINS TEXT %q", c.Field, ignoreCasePrefix(c.IgnoreCase), c.Value)
+}
+
+// Type returns the name of the condition.
+func (c *ContainsTextCondition) Type() string {
+	return ContainsTextConditionType
+}
+
+func ignoreCasePrefix(ignoreCase bool) string {
+	if ignoreCase {
+		return "I"
+	}
+	return ""
+}
+
+func applyStartsWith(obj any, condition filter.Condition) (bool, error) {
+	c, ok := condition.(*StartsWithCondition)
+	if !ok {
+		return false, fmt.Errorf("condition is no StartsWithCondition")
+	}
+	return matchText(obj, c.Field, c.Value, c.IgnoreCase, strings.HasPrefix)
+}
+
+func applyEndsWith(obj any, condition filter.Condition) (bool, error) {
+	c, ok := condition.(*EndsWithCondition)
+	if !ok {
+		return false, fmt.Errorf("condition is no EndsWithCondition")
+	}
+	return matchText(obj, c.Field, c.Value, c.IgnoreCase, strings.HasSuffix)
+}
+
+func applyContainsText(obj any, condition filter.Condition) (bool, error) {
+	c, ok := condition.(*ContainsTextCondition)
+	if !ok {
+		return false, fmt.Errorf("condition is no ContainsTextCondition")
+	}
+	return matchText(obj, c.Field, c.Value, c.IgnoreCase, strings.Contains)
+}
+
+// matchText reports whether the string field of the object or any element of the slice or
+// array field matches the value. Slices and arrays with a string representation, like net.IP,
+// are matched as a whole. Nil values and elements do not match.
+func matchText(obj any, name string, value string, ignoreCase bool, match func(s, value string) bool) (bool, error) {
+	field, err := getField(obj, name)
+	if err != nil {
+		return false, err
+	}
+	if ignoreCase {
+		value = strings.ToLower(value)
+	}
+	matches := func(v reflect.Value) (bool, error) {
+		s, err := stringValue(v)
+		if errors.Is(err, errNilValue) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if ignoreCase {
+			s = strings.ToLower(s)
+		}
+		return match(s, value), nil
+	}
+
+	field = indirect(field)
+	if (field.Kind() != reflect.Slice && field.Kind() != reflect.Array) || hasStringRepresentation(field.Type()) {
+		return matches(field)
+	}
+	for i := 0; i < field.Len(); i++ {
+		applies, err := matches(field.Index(i))
+		if err != nil || applies {
+			return applies, err
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,58 @@
+package filterobject
+
+import (
+	"github.com/stretchr/testify/require"
+	"github.com/xafelium/filter"
+	"net"
+	"testing"
+)
+
+type TextTestObject struct {
+	Name      string
+	Nickname  *string
+	Nicknames []string
+	States    []State
+	Priority  Priority
+	Address   net.IP
+	Count     int
+}
+
+func TestApplyTextConditions(t *testing.T) {
+	obj := TextTestObject{
+		Name:      "Zoë Smith",
+		Nicknames: []string{"zo", "Smithy"},
+		States:    []State{"open"},
+		Priority:  PriorityHigh,
+		Address:   net.ParseIP("10.0.0.1"),
+	}
+	for condition, expected := range map[filter.Condition]bool{
+		StartsWith("name", "Zoë"):                  true,
+		StartsWith("name", "zoë"):                  false,
+		StartsWithIgnoreCase("name", "ZOË"):        true,
+		EndsWith("name", "Smith"):                  true,
+		EndsWith("name", "smith"):                  false,
+		EndsWithIgnoreCase("name", "SMITH"):        true,
+		ContainsText("name", "ë S"):                true,
+		ContainsText("name", "ë s"):                false,
+		ContainsTextIgnoreCase("name", "Ë S"):      true,
+		StartsWith("name", ".*"):                   false,
+		StartsWith("nickname", ""):                 false,
+		StartsWith("nicknames", "Smi"):             true,
+		StartsWith("nicknames", "smi"):             false,
+		StartsWithIgnoreCase("nicknames", "smi"):   true,
+		EndsWith("nicknames", "o"):                 true,
+		ContainsText("nicknames", "mit"):           true,
+		ContainsText("nicknames", "x"):             false,
+		EndsWith("states", "en"):                   true,
+		StartsWith("priority", "hi"):               true,
+		StartsWith("address", "10.0."):             true,
+		filter.Not(ContainsText("nicknames", "x")): true,
+	} {
+		applies, err := FilterApplies(obj, condition)
+		require.NoError(t, err, condition.String())
+		require.Equal(t, expected, applies, condition.String())
+	}
+
+	_, err := FilterApplies(obj, StartsWith("count", "1"))
+	require.EqualError(t, err, "cannot convert value of type int into a string")
+}
@@ -0,0 +1,178 @@
+package filterobject
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+const (
+	dateLayout      = "2006-01-02"
+	timeOfDayLayout = "15:04:05"
+)
+
+// TimeUnit is the precision used to compare times.
+type TimeUnit int
+
+const (
+	Nanosecond TimeUnit = iota
+	Second
+	Minute
+	Hour
+	Day
+	Week
+	Month
+	Year
+)
+
+// TimeValue is a condition value which compares time fields with a reduced precision.
+// Both times are converted into Location and truncated to Unit before they are compared.
+// If Location is nil, the location of Time is used.
+type TimeValue struct {
+	Time     time.Time
+	Unit     TimeUnit
+	Location *time.Location
+}
+
+// TruncatedTime creates a new TimeValue.
+func TruncatedTime(t time.Time, unit TimeUnit, loc *time.Location) TimeValue {
+	return TimeValue{
+		Time:     t,
+		Unit:     unit,
+		Location: loc,
+	}
+}
+
+// Date creates a TimeValue comparing time fields by their calendar day.
+func Date(year int, month time.Month, day int, loc *time.Location) TimeValue {
+	return TruncatedTime(time.Date(year, month, day, 0, 0, 0, 0, loc), Day, loc)
+}
+
+func (v TimeValue) location() *time.Location {
+	if v.Location != nil {
+		return v.Location
+	}
+	return v.Time.Location()
+}
+
+// TimeOfDay is a condition value which compares only the clock time of time fields.
+// Clock times are compared at second precision, so 09:00:00.5 equals 09:00:00.
+// If Location is nil, the clock time is taken in the location of the field.
+type TimeOfDay struct {
+	Hour     int
+	Minute   int
+	Second   int
+	Location *time.Location
+}
+
+func (v TimeOfDay) sinceMidnight() time.Duration {
+	return time.Duration(v.Hour)*time.Hour + time.Duration(v.Minute)*time.Minute + time.Duration(v.Second)*time.Second
+}
+
+// compareTime compares time and duration fields. The second return value is false if
+// neither the field nor the value is a time specific type.
+func compareTime(field, value reflect.Value) (int, bool, error) {
+	if field.Type() == durationType && value.Kind() == reflect.String {
+		d, err := time.ParseDuration(value.String())
+		if err != nil {
+			return 0, true, fmt.Errorf("invalid duration value: %w", err)
+		}
+		return compareOrdered(field.Int(), int64(d)), true, nil
+	}
+	if field.Type() != timeType {
+		return 0, false, nil
+	}
+	if !field.CanInterface() || !value.CanInterface() {
+		return 0, true, fmt.Errorf("cannot access values of type %s and %s", field.Type(), value.Type())
+	}
+	t := field.Interface().(time.Time)
+	switch operand := value.Interface().(type) {
+	case time.Time:
+		return compareInstants(t, operand), true, nil
+	case TimeValue:
+		loc := operand.location()
+		return compareInstants(truncateTime(t, operand.Unit, loc), truncateTime(operand.Time, operand.Unit, loc)), true, nil
+	case RelativeTime:
+		return compareInstants(t, operand.Resolve(currentClock().Now())), true, nil
+	case TimeOfDay:
+		loc := operand.Location
+		if loc == nil {
+			loc = t.Location()
+		}
+		return compareOrdered(timeOfDay(t.In(loc)).Truncate(time.Second), operand.sinceMidnight()), true, nil
+	case string:
+		c, err := compareTimeString(t, operand)
+		return c, true, err
+	default:
+		return 0, true, fmt.Errorf("cannot compare variables of type %s and %s", field.Type(), value.Type())
+	}
+}
+
+// compareTimeString compares a time with a string in RFC 3339, date-only, time-of-day or
+// relative time format.
+// Date-only and time-of-day strings are interpreted in the location of the time and compared
+// at the precision of the string.
+func compareTimeString(t time.Time, s string) (int, error) {
+	if parsed, err := time.Parse(time.RFC3339, s); err == nil {
+		return compareInstants(t, parsed), nil
+	}
+	if parsed, err := time.ParseInLocation(dateLayout, s, t.Location()); err == nil {
+		return compareInstants(truncateTime(t, Day, t.Location()), parsed), nil
+	}
+	if parsed, err := time.Parse(timeOfDayLayout, s); err == nil {
+		return compareOrdered(timeOfDay(t).Truncate(time.Second), timeOfDay(parsed)), nil
+	}
+	if parsed, err := time.Parse("15:04", s); err == nil {
+		return compareOrdered(timeOfDay(t).Truncate(time.Minute), timeOfDay(parsed)), nil
+	}
+	if relative, err := ParseRelativeTime(s); err == nil {
+		return compareInstants(t, relative.Resolve(currentClock().Now())), nil
+	}
+	return 0, fmt.Errorf("invalid time value: %s", s)
+}
+
+func compareInstants(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	default:
+		return 0
+	}
+}
+
+func timeOfDay(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour +
+		time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second +
+		time.Duration(t.Nanosecond())
+}
+
+func truncateTime(t time.Time, unit TimeUnit, loc *time.Location) time.Time {
+	t = t.In(loc)
+	switch unit {
+	case Second:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, loc)
+	case Minute:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc)
+	case Hour:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc)
+	case Day:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	case Week:
+		daysSinceMonday := (int(t.Weekday()) + 6) % 7
+		return time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, loc)
+	case Month:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc)
+	case Year:
+		return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, loc)
+	default:
+		return t
+	}
+}
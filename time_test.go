@@ -0,0 +1,225 @@
+package filterobject
+
+import (
+	"github.com/stretchr/testify/require"
+	"github.com/xafelium/filter"
+	"testing"
+	"time"
+)
+
+type TimeTestObject struct {
+	CreatedAt time.Time
+	DeletedAt *time.Time
+	Timeout   time.Duration
+}
+
+func TestTimeEquality(t *testing.T) {
+	var applies bool
+	var err error
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	createdAt := time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC)
+	obj := TimeTestObject{
+		CreatedAt: createdAt,
+		DeletedAt: &createdAt,
+	}
+
+	// Same instant in a different location
+	applies, err = applyEquals(obj, filter.Equals("createdAt", createdAt.In(berlin)))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	// Monotonic clock readings are ignored
+	now := time.Now()
+	applies, err = applyEquals(TimeTestObject{CreatedAt: now.Round(0)}, filter.Equals("createdAt", now))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	// Time pointers
+	applies, err = applyEquals(obj, filter.Equals("deletedAt", createdAt))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyEquals(obj, filter.Equals("createdAt", &createdAt))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyEquals(TimeTestObject{}, filter.Equals("deletedAt", createdAt))
+	require.NoError(t, err)
+	require.False(t, applies)
+
+	applies, err = applyEquals(TimeTestObject{}, filter.Equals("deletedAt", nil))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	// RFC 3339 strings
+	applies, err = applyEquals(obj, filter.Equals("createdAt", "2024-03-15T11:30:00+01:00"))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyEquals(obj, filter.Equals("createdAt", "2024-03-15T10:30:01Z"))
+	require.NoError(t, err)
+	require.False(t, applies)
+}
+
+func TestTimeOrdering(t *testing.T) {
+	var applies bool
+	var err error
+	createdAt := time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC)
+	obj := TimeTestObject{
+		CreatedAt: createdAt,
+		DeletedAt: &createdAt,
+		Timeout:   90 * time.Second,
+	}
+
+	// Time pointers
+	applies, err = applyGreaterThan(obj, filter.GreaterThan("deletedAt", createdAt.Add(-time.Hour)))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyLowerThan(TimeTestObject{}, filter.LowerThan("deletedAt", createdAt))
+	require.NoError(t, err)
+	require.False(t, applies)
+
+	// RFC 3339 strings
+	applies, err = applyGreaterThan(obj, filter.GreaterThan("createdAt", "2024-03-15T10:00:00Z"))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyLowerThan(obj, filter.LowerThan("createdAt", "2024-03-15T10:00:00Z"))
+	require.NoError(t, err)
+	require.False(t, applies)
+
+	// Date-only strings
+	applies, err = applyEquals(obj, filter.Equals("createdAt", "2024-03-15"))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyGreaterThan(obj, filter.GreaterThan("createdAt", "2024-03-14"))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyLowerThanOrEqual(obj, filter.LowerThanOrEqual("createdAt", "2024-03-15"))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	// Time-of-day strings
+	applies, err = applyGreaterThan(obj, filter.GreaterThan("createdAt", "09:00"))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyLowerThan(obj, filter.LowerThan("createdAt", "10:30:00"))
+	require.NoError(t, err)
+	require.False(t, applies)
+
+	// Durations
+	applies, err = applyGreaterThan(obj, filter.GreaterThan("timeout", time.Minute))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyLowerThan(obj, filter.LowerThan("timeout", "1m"))
+	require.NoError(t, err)
+	require.False(t, applies)
+
+	applies, err = applyEquals(obj, filter.Equals("timeout", "1m30s"))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	// Errors
+	applies, err = applyGreaterThan(obj, filter.GreaterThan("createdAt", "someday"))
+	require.Error(t, err)
+	require.False(t, applies)
+
+	applies, err = applyGreaterThan(obj, filter.GreaterThan("timeout", "forever"))
+	require.Error(t, err)
+	require.False(t, applies)
+
+	applies, err = applyGreaterThan(obj, filter.GreaterThan("createdAt", 42))
+	require.Error(t, err)
+	require.False(t, applies)
+}
+
+func TestTimeValue(t *testing.T) {
+	var applies bool
+	var err error
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	obj := TimeTestObject{
+		CreatedAt: time.Date(2024, 3, 15, 20, 30, 0, 0, time.UTC),
+	}
+
+	applies, err = applyEquals(obj, filter.Equals("createdAt", Date(2024, 3, 15, time.UTC)))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	// 20:30 UTC is already the next day in Tokyo
+	applies, err = applyEquals(obj, filter.Equals("createdAt", Date(2024, 3, 15, tokyo)))
+	require.NoError(t, err)
+	require.False(t, applies)
+
+	applies, err = applyEquals(obj, filter.Equals("createdAt", Date(2024, 3, 16, tokyo)))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyGreaterThan(obj, filter.GreaterThan("createdAt", Date(2024, 3, 15, time.UTC)))
+	require.NoError(t, err)
+	require.False(t, applies)
+
+	applies, err = applyGreaterThanOrEqual(obj, filter.GreaterThanOrEqual("createdAt", Date(2024, 3, 15, time.UTC)))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyEquals(obj, filter.Equals("createdAt", TruncatedTime(obj.CreatedAt.Add(10*time.Minute), Hour, nil)))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyEquals(obj, filter.Equals("createdAt", TruncatedTime(obj.CreatedAt.Add(40*time.Minute), Hour, nil)))
+	require.NoError(t, err)
+	require.False(t, applies)
+
+	applies, err = applyLowerThan(obj, filter.LowerThan("createdAt", TruncatedTime(obj.CreatedAt.Add(40*time.Minute), Hour, nil)))
+	require.NoError(t, err)
+	require.True(t, applies)
+}
+
+func TestTimeOfDay(t *testing.T) {
+	var applies bool
+	var err error
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	obj := TimeTestObject{
+		CreatedAt: time.Date(2024, 3, 15, 20, 30, 0, 0, time.UTC),
+	}
+
+	applies, err = applyGreaterThan(obj, filter.GreaterThan("createdAt", TimeOfDay{Hour: 18}))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyEquals(obj, filter.Equals("createdAt", TimeOfDay{Hour: 20, Minute: 30}))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	// 20:30 UTC is 05:30 in Tokyo
+	applies, err = applyLowerThan(obj, filter.LowerThan("createdAt", TimeOfDay{Hour: 6, Location: tokyo}))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	// Clock times are compared at the precision of the value
+	obj.CreatedAt = time.Date(2024, 3, 15, 9, 0, 0, 500_000_000, time.UTC)
+	applies, err = applyEquals(obj, filter.Equals("createdAt", TimeOfDay{Hour: 9}))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	applies, err = applyGreaterThan(obj, filter.GreaterThan("createdAt", TimeOfDay{Hour: 9}))
+	require.NoError(t, err)
+	require.False(t, applies)
+
+	applies, err = applyEquals(obj, filter.Equals("createdAt", "09:00:00"))
+	require.NoError(t, err)
+	require.True(t, applies)
+
+	obj.CreatedAt = time.Date(2024, 3, 15, 9, 0, 30, 0, time.UTC)
+	applies, err = applyEquals(obj, filter.Equals("createdAt", "09:00"))
+	require.NoError(t, err)
+	require.True(t, applies)
+}